@@ -0,0 +1,217 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/fgday/quotaday/pkg/quote"
+)
+
+//go:embed embed.js
+var embedScript []byte
+
+const (
+	// embedWidth and embedHeight are the size in pixels of the iframes
+	// embedding quotes in oEmbed responses, unless smaller ones are requested
+	embedWidth  = 600
+	embedHeight = 240
+)
+
+// GET embed serves the compact HTML page of a quotation to embed in iframes
+func (s *Server) GetEmbed(w http.ResponseWriter, r *http.Request, params GetEmbedParams) {
+	opts, err := renderOptions(rendering{theme: params.Theme})
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	opts.BaseURL = s.baseURL(w, r)
+	renderer, _ := s.renderers.Lookup("text/html")
+	embedder, ok := renderer.(quote.EmbedRenderer)
+	if !ok {
+		writeError(w, http.StatusNotAcceptable, "no renderer of embedded quotes")
+		return
+	}
+
+	sel := selection{params.Id, params.Mode, params.Tag, params.Author}
+	q, err := s.selectQuote(sel)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+
+	if sel.daily() {
+		setCacheUntilNextDay(w, s.now().In(s.location))
+	}
+	w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+	if err := embedder.RenderEmbed(w, q, opts); err != nil {
+		requestLogger(r).Warn("error writing response", "error", err)
+		return
+	}
+	recordServed(r, q)
+}
+
+// GET embed.js serves the script rendering quotations in other pages
+func (s *Server) GetEmbedScript(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/javascript; charset=UTF-8")
+	w.Header().Set("Cache-Control", "public, max-age=86400")
+	if _, err := w.Write(embedScript); err != nil {
+		requestLogger(r).Warn("error writing response", "error", err)
+	}
+}
+
+// GET oembed serves the oEmbed response embedding the quotation linked by
+// a URL of the server
+func (s *Server) GetOEmbed(w http.ResponseWriter, r *http.Request, params GetOEmbedParams) {
+	allowAnyOrigin(w)
+	if params.Format != nil && *params.Format != "json" {
+		writeError(w, http.StatusNotImplemented, fmt.Sprintf("unsupported format %q", *params.Format))
+		return
+	}
+	width, height := embedWidth, embedHeight
+	for _, limit := range []struct {
+		name  string
+		value *int
+		size  *int
+	}{
+		{"maxwidth", params.Maxwidth, &width},
+		{"maxheight", params.Maxheight, &height},
+	} {
+		if limit.value == nil {
+			continue
+		}
+		if *limit.value < 1 {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid %s %d", limit.name, *limit.value))
+			return
+		}
+		*limit.size = min(*limit.size, *limit.value)
+	}
+
+	base := s.baseURL(w, r)
+	sel, query, err := embedSelection(base, params.Url)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	q, err := s.selectQuote(sel)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	title := "Quotaday"
+	if q.Author != "" {
+		title = q.Author + " - " + title
+	}
+	src := base + "/embed?" + query.Encode()
+	provider := "Quotaday"
+	resp := OEmbed{
+		Type:    "rich",
+		Version: "1.0",
+		Title:   &title,
+		Html: fmt.Sprintf(`<iframe src="%s" width="%d" height="%d" title="%s" style="border:0" loading="lazy"></iframe>`,
+			html.EscapeString(src), width, height, html.EscapeString(title)),
+		Width:        width,
+		Height:       height,
+		ProviderName: &provider,
+		ProviderUrl:  &base,
+	}
+	if q.Author != "" {
+		resp.AuthorName = &q.Author
+	}
+	if sel.daily() {
+		now := s.now().In(s.location)
+		cacheAge := int(quote.NextDay(now).Sub(now).Seconds())
+		resp.CacheAge = &cacheAge
+		setCacheUntilNextDay(w, now)
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		requestLogger(r).Warn("error writing response", "error", err)
+	}
+}
+
+// embedSelection returns the selection of the quotation linked by rawURL,
+// which must be a URL of the server at base, along with the query of the
+// page embedding it
+func embedSelection(base, rawURL string) (selection, url.Values, error) {
+	var sel selection
+	notFound := fmt.Errorf("no quotation at %q", rawURL)
+
+	b, err := url.Parse(base)
+	if err != nil {
+		return sel, nil, err
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || !strings.EqualFold(u.Host, b.Host) {
+		return sel, nil, notFound
+	}
+	path, ok := strings.CutPrefix(u.Path, b.Path)
+	if !ok {
+		return sel, nil, notFound
+	}
+
+	linked := u.Query()
+	query := url.Values{}
+	switch {
+	case path == "/quote/today":
+		query.Set("mode", string(Daily))
+	case path == "/quote" || path == "/quote/image" || path == "/embed":
+		for _, name := range []string{"id", "mode", "tag", "author"} {
+			if v := linked.Get(name); v != "" {
+				query.Set(name, v)
+			}
+		}
+	case strings.HasPrefix(path, "/quotes/"):
+		query.Set("id", strings.TrimPrefix(path, "/quotes/"))
+	default:
+		return sel, nil, notFound
+	}
+	if theme := linked.Get("theme"); theme != "" {
+		query.Set("theme", theme)
+	}
+
+	if v := query.Get("id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			return sel, nil, notFound
+		}
+		sel.id = &id
+	}
+	if v := query.Get("mode"); v != "" {
+		mode := Mode(v)
+		if mode != Daily && mode != Random {
+			return sel, nil, notFound
+		}
+		sel.mode = &mode
+	}
+	if v := query.Get("tag"); v != "" {
+		sel.tag = &v
+	}
+	if v := query.Get("author"); v != "" {
+		sel.author = &v
+	}
+	return sel, query, nil
+}
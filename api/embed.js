@@ -0,0 +1,58 @@
+/*
+ * Quotaday embed script: renders a quotation right after the script
+ * element including it, e.g.:
+ *
+ *   <script src="https://quote.example.com/embed.js" data-mode="daily" async></script>
+ *
+ * The quotation is chosen with the data-id, data-mode, data-tag and
+ * data-author attributes, as the matching query parameters of /quote.
+ * The rendered figure has the "quotaday" class so pages can style it.
+ */
+(function () {
+  "use strict";
+
+  var script = document.currentScript;
+  if (!script || !window.fetch) {
+    return;
+  }
+
+  var url = new URL("quote", script.src);
+  ["id", "mode", "tag", "author"].forEach(function (name) {
+    var value = script.getAttribute("data-" + name);
+    if (value) {
+      url.searchParams.set(name, value);
+    }
+  });
+
+  var figure = document.createElement("figure");
+  figure.className = "quotaday";
+  figure.style.margin = "1em 0";
+  script.parentNode.insertBefore(figure, script.nextSibling);
+
+  fetch(url, { headers: { Accept: "application/json" } })
+    .then(function (response) {
+      if (!response.ok) {
+        throw new Error(response.status + " " + response.statusText);
+      }
+      return response.json();
+    })
+    .then(function (quote) {
+      var blockquote = document.createElement("blockquote");
+      blockquote.style.margin = "0";
+      blockquote.style.fontStyle = "italic";
+      var q = document.createElement("q");
+      q.textContent = quote.quote;
+      blockquote.appendChild(q);
+      figure.appendChild(blockquote);
+
+      if (quote.author) {
+        var caption = document.createElement("figcaption");
+        caption.textContent = "— " + quote.author;
+        figure.appendChild(caption);
+      }
+    })
+    .catch(function (err) {
+      figure.remove();
+      console.warn("Quotaday: cannot fetch the quotation:", err);
+    });
+})();
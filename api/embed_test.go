@@ -0,0 +1,134 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetEmbed(t *testing.T) {
+	h := HandlerFromMux(NewServer(WithBaseURL("https://quote.example.com")), http.NewServeMux())
+	tests := []struct {
+		target string
+		code   int
+		body   string
+	}{
+		{"/embed?id=1", http.StatusOK, `<a href="https://quote.example.com/quotes/1">Eat the frog first.</a>`},
+		{"/embed?id=1&theme=dark", http.StatusOK, `<body class="theme-dark">`},
+		{"/embed?mode=daily", http.StatusOK, `<base target="_blank">`},
+		{"/embed?id=100", http.StatusNotFound, ""},
+		{"/embed?id=1&theme=sepia", http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		resp := serveRequest(t, h, "GET", tt.target, "")
+		if resp.StatusCode != tt.code {
+			t.Errorf("%s: expected %d, got %d", tt.target, tt.code, resp.StatusCode)
+			continue
+		}
+		body, _ := io.ReadAll(resp.Body)
+		if !strings.Contains(string(body), tt.body) {
+			t.Errorf("%s: expected body containing %q, got %s", tt.target, tt.body, body)
+		}
+	}
+}
+
+func TestGetEmbedScript(t *testing.T) {
+	h := HandlerFromMux(NewServer(), http.NewServeMux())
+	resp := serveRequest(t, h, "GET", "/embed.js", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/javascript") {
+		t.Errorf("Unexpected content type %q", ct)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(body), "document.currentScript") {
+		t.Errorf("Unexpected script %s", body)
+	}
+
+	// The script fetches quotes from other sites
+	resp = serveRequest(t, h, "GET", "/quote?id=1", "")
+	if origin := resp.Header.Get("Access-Control-Allow-Origin"); origin != "*" {
+		t.Errorf("Expected quotes allowed to any origin, got %q", origin)
+	}
+}
+
+func TestGetOEmbed(t *testing.T) {
+	s := NewServer(WithBaseURL("https://quote.example.com"))
+	s.now = func() time.Time { return time.Date(2025, 3, 14, 23, 0, 0, 0, time.UTC) }
+	s.location = time.UTC
+	h := HandlerFromMux(s, http.NewServeMux())
+	tests := []struct {
+		name   string
+		url    string
+		extra  string
+		code   int
+		src    string
+		width  int
+		height int
+	}{
+		{"quote by id", "https://quote.example.com/quotes/1", "", http.StatusOK, "https://quote.example.com/embed?id=1", 600, 240},
+		{"quote query", "https://quote.example.com/quote?id=1&theme=dark&format=json", "", http.StatusOK, "https://quote.example.com/embed?id=1&amp;theme=dark", 600, 240},
+		{"daily", "http://QUOTE.example.com/quote/today", "&maxwidth=300&maxheight=1000", http.StatusOK, "https://quote.example.com/embed?mode=daily", 300, 240},
+		{"xml", "https://quote.example.com/quotes/1", "&format=xml", http.StatusNotImplemented, "", 0, 0},
+		{"bad maxwidth", "https://quote.example.com/quotes/1", "&maxwidth=0", http.StatusBadRequest, "", 0, 0},
+		{"other host", "https://example.org/quotes/1", "", http.StatusNotFound, "", 0, 0},
+		{"other path", "https://quote.example.com/tags", "", http.StatusNotFound, "", 0, 0},
+		{"unknown quote", "https://quote.example.com/quotes/100", "", http.StatusNotFound, "", 0, 0},
+		{"bad id", "https://quote.example.com/quotes/one", "", http.StatusNotFound, "", 0, 0},
+		{"bad mode", "https://quote.example.com/quote?mode=weekly", "", http.StatusNotFound, "", 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := serveRequest(t, h, "GET", "/oembed?url="+url.QueryEscape(tt.url)+tt.extra, "")
+			if resp.StatusCode != tt.code {
+				t.Fatalf("Expected %d, got %d", tt.code, resp.StatusCode)
+			}
+			if tt.code != http.StatusOK {
+				return
+			}
+			var got OEmbed
+			if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+				t.Fatalf("Failed to decode JSON: %v", err)
+			}
+			if got.Type != "rich" || got.Version != "1.0" {
+				t.Errorf("Unexpected type %q and version %q", got.Type, got.Version)
+			}
+			if got.Width != tt.width || got.Height != tt.height {
+				t.Errorf("Expected %dx%d, got %dx%d", tt.width, tt.height, got.Width, got.Height)
+			}
+			if !strings.Contains(got.Html, `<iframe src="`+tt.src+`"`) {
+				t.Errorf("Expected an iframe of %q, got %q", tt.src, got.Html)
+			}
+		})
+	}
+
+	resp := serveRequest(t, h, "GET", "/oembed?url="+url.QueryEscape("https://quote.example.com/quote/today"), "")
+	var daily OEmbed
+	if err := json.NewDecoder(resp.Body).Decode(&daily); err != nil {
+		t.Fatalf("Failed to decode JSON: %v", err)
+	}
+	if daily.CacheAge == nil || *daily.CacheAge != 3600 {
+		t.Errorf("Expected the quote of the day cached for an hour, got %v", daily.CacheAge)
+	}
+}
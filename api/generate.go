@@ -1,3 +1,4 @@
 package api
 
+//go:generate go run ./internal/specgen openapi.yaml
 //go:generate go run github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen -config config.yaml openapi.yaml
@@ -0,0 +1,55 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/fgday/quotaday/pkg/quote"
+)
+
+// GET healthz tells that the server is alive. Probes are logged quietly,
+// as they are frequent.
+func (s *Server) GetHealth(w http.ResponseWriter, r *http.Request) {
+	logQuietly(r)
+	writeHealth(w, r, http.StatusOK, Health{Status: Ok})
+}
+
+// GET readyz tells whether the quote Store can serve requests
+func (s *Server) GetReadiness(w http.ResponseWriter, r *http.Request) {
+	logQuietly(r)
+	if checker, ok := s.store.(quote.HealthChecker); ok {
+		if err := checker.CheckHealth(); err != nil {
+			msg := err.Error()
+			writeHealth(w, r, http.StatusServiceUnavailable, Health{Status: Unavailable, Error: &msg})
+			return
+		}
+	}
+	writeHealth(w, r, http.StatusOK, Health{Status: Ok})
+}
+
+// writeHealth replies with the given HTTP status code and health status,
+// which must never be cached
+func writeHealth(w http.ResponseWriter, r *http.Request, code int, health Health) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(health); err != nil {
+		requestLogger(r).Warn("error writing health", "error", err)
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fgday/quotaday/pkg/quote"
+)
+
+func TestHealth(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "data")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	fs, err := quote.NewFileStore(filepath.Join(dir, "quotes.json"))
+	if err != nil {
+		t.Fatalf("NewFileStore failed: %v", err)
+	}
+	h := HandlerFromMux(NewServer(WithStore(fs)), http.NewServeMux())
+
+	check := func(target string, code int, status HealthStatus) {
+		t.Helper()
+		resp := serveRequest(t, h, "GET", target, "")
+		if resp.StatusCode != code {
+			t.Errorf("%s: expected %d, got %d", target, code, resp.StatusCode)
+		}
+		var got Health
+		if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+			t.Fatalf("%s: failed to decode JSON: %v", target, err)
+		}
+		if got.Status != status {
+			t.Errorf("%s: expected status %q, got %q", target, status, got.Status)
+		}
+		if cc := resp.Header.Get("Cache-Control"); cc != "no-store" {
+			t.Errorf("%s: unexpected Cache-Control %q", target, cc)
+		}
+	}
+	check("/healthz", http.StatusOK, Ok)
+	check("/readyz", http.StatusOK, Ok)
+
+	// The data file cannot be written anymore
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	check("/healthz", http.StatusOK, Ok)
+	check("/readyz", http.StatusServiceUnavailable, Unavailable)
+
+	// The memory store is always ready
+	memory := HandlerFromMux(NewServer(), http.NewServeMux())
+	if resp := serveRequest(t, memory, "GET", "/readyz", ""); resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected the memory store ready, got %d", resp.StatusCode)
+	}
+}
@@ -1,132 +1,741 @@
 package api
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"hash/fnv"
+	"io"
+	"mime"
 	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/fgday/quotaday/pkg/quote"
 )
 
 type Server struct {
-	qb *quote.QuoteBook
+	store quote.Store
+	// location is the timezone where days roll over for the quote of the day
+	location *time.Location
+	// renderers write quotes in the media types clients can ask for
+	renderers *quote.Registry
+	// base is the URL the Server is reachable at, guessed from each
+	// request if empty
+	base string
+	// proxies are the networks of the proxies whose X-Forwarded-Host and
+	// X-Forwarded-Proto headers are trusted to guess base
+	proxies []netip.Prefix
+	now     func() time.Time
 }
 
 var _ ServerInterface = (*Server)(nil)
 
-func NewServer() *Server {
-	server := Server{}
-	server.qb = quote.New()
-	server.qb.FillExample()
+// Option configures optional Server settings
+type Option func(*Server)
+
+// WithStore makes the Server use store instead of an in-memory QuoteBook
+// filled with the example quotes
+func WithStore(store quote.Store) Option {
+	return func(s *Server) {
+		s.store = store
+	}
+}
+
+// WithLocation sets the timezone where days roll over for the quote of the day,
+// the local one is used by default
+func WithLocation(loc *time.Location) Option {
+	return func(s *Server) {
+		s.location = loc
+	}
+}
+
+// WithRenderers sets the renderers used to serve quotes, the built-in ones
+// are used by default
+func WithRenderers(renderers *quote.Registry) Option {
+	return func(s *Server) {
+		s.renderers = renderers
+	}
+}
+
+// WithBaseURL sets the URL the Server is reachable at, used to link its
+// resources. By default it is guessed from each request.
+func WithBaseURL(base string) Option {
+	return func(s *Server) {
+		s.base = strings.TrimRight(base, "/")
+	}
+}
+
+// WithTrustedProxies makes the Server trust the X-Forwarded-Host and
+// X-Forwarded-Proto headers of the requests coming from the given networks
+// to guess the URL it is reachable at. They are ignored by default, as any
+// client could forge them.
+func WithTrustedProxies(proxies []netip.Prefix) Option {
+	return func(s *Server) {
+		s.proxies = proxies
+	}
+}
+
+func NewServer(opts ...Option) *Server {
+	server := Server{
+		location:  time.Local,
+		renderers: quote.NewRegistry(),
+		now:       time.Now,
+	}
+	for _, opt := range opts {
+		opt(&server)
+	}
+	if server.store == nil {
+		qb := quote.New()
+		qb.FillExample()
+		server.store = qb
+	}
 	return &server
 }
 
 // GET quote serves a quotation from the available ones
 func (s *Server) GetQuote(w http.ResponseWriter, r *http.Request, params GetQuoteParams) {
-	log.Println(getRemoteHostInfo(r))
+	allowAnyOrigin(w)
+	s.serveQuote(w, r, selection{params.Id, params.Mode, params.Tag, params.Author},
+		rendering{format: params.Format, width: params.Width, theme: params.Theme})
+}
 
-	var q *quote.Quotation
-	var err error
-	if params.Id == nil {
-		q, err = s.qb.RandomQuotation()
-	} else {
-		q, err = s.qb.GetQuote(*params.Id)
+// GET quote/image serves a quotation from the available ones as an image
+func (s *Server) GetQuoteImage(w http.ResponseWriter, r *http.Request, params GetQuoteImageParams) {
+	allowAnyOrigin(w)
+	// Not nil, so that no other content types are offered without images
+	images := []string{}
+	for _, ct := range s.renderers.ContentTypes() {
+		if strings.HasPrefix(ct, "image/") {
+			images = append(images, ct)
+		}
 	}
+	s.serveQuote(w, r, selection{params.Id, params.Mode, params.Tag, params.Author},
+		rendering{format: params.Format, offers: images})
+}
+
+// selection holds the query parameters choosing which quotation to serve
+type selection struct {
+	id     *int
+	mode   *Mode
+	tag    *string
+	author *string
+}
 
+// daily tells whether sel chooses the quote of the day
+func (sel selection) daily() bool {
+	return sel.id == nil && sel.mode != nil && *sel.mode == Daily
+}
+
+// selectQuote returns the quotation chosen by sel
+func (s *Server) selectQuote(sel selection) (*quote.Quotation, error) {
+	if sel.daily() {
+		return quote.DailyQuotation(s.store, s.now().In(s.location))
+	}
+	if sel.id != nil {
+		return s.store.GetQuote(*sel.id)
+	}
+	var filter quote.Filter
+	if sel.tag != nil {
+		filter.Tag = *sel.tag
+	}
+	if sel.author != nil {
+		filter.Author = *sel.author
+	}
+	return s.store.RandomQuotationMatching(filter)
+}
+
+// serveQuote serves the quotation chosen by sel
+func (s *Server) serveQuote(w http.ResponseWriter, r *http.Request, sel selection, rnd rendering) {
+	if sel.daily() {
+		s.writeDailyQuote(w, r, rnd)
+		return
+	}
+
+	q, err := s.selectQuote(sel)
 	if err != nil {
-		w.WriteHeader(http.StatusBadRequest)
-		_, _ = w.Write([]byte(err.Error()))
-	}
-
-	mimeTypes := r.Header.Values("Accept")
-	// if "Accept" header is not present  just assume any  MIME type is fine
-	if len(mimeTypes) == 0 {
-		mimeTypes = []string{"*/*"}
-	}
-
-	for _, mt := range mimeTypes {
-		for _, val := range strings.Split(mt, ",") {
-			var err error
-			switch val {
-			case "text/html":
-				w.Header().Set("Content-Type", "text/html; charset=UTF-8")
-				err = q.WriteHTML(w)
-			case "application/json", "*/*":
-				w.Header().Set("Content-Type", "application/json; charset=UTF-8")
-				err = q.WriteJSON(w)
-			default:
-				log.Printf("Skipping MIME type %q", val)
-				continue
-			}
-
-			if err != nil {
-				log.Printf("error writing quote: %s", err)
-				return
-			}
-			log.Printf("Serving MIME type %q", val)
-			return
+		writeStoreError(w, err)
+		return
+	}
+
+	s.writeQuote(w, r, q, rnd)
+}
+
+// GET quote/today serves the quote of the day
+func (s *Server) GetDailyQuote(w http.ResponseWriter, r *http.Request, params GetDailyQuoteParams) {
+	allowAnyOrigin(w)
+	s.writeDailyQuote(w, r, rendering{format: params.Format, width: params.Width, theme: params.Theme})
+}
+
+// writeDailyQuote serves the quote of the day, allowing clients
+// to cache it until the next rollover
+func (s *Server) writeDailyQuote(w http.ResponseWriter, r *http.Request, rnd rendering) {
+	now := s.now().In(s.location)
+	q, err := quote.DailyQuotation(s.store, now)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+
+	setCacheUntilNextDay(w, now)
+	s.writeQuote(w, r, q, rnd)
+}
+
+// setCacheUntilNextDay allows clients to cache the response until the
+// quote of the day rolls over
+func setCacheUntilNextDay(w http.ResponseWriter, now time.Time) {
+	expires := quote.NextDay(now)
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(expires.Sub(now).Seconds())))
+	w.Header().Set("Expires", expires.UTC().Format(http.TimeFormat))
+}
+
+// defaultFeedDays is the number of days in the feeds by default
+const defaultFeedDays = 10
+
+// GET feed.rss serves the RSS feed of the quotes of the day
+func (s *Server) GetRssFeed(w http.ResponseWriter, r *http.Request, params GetRssFeedParams) {
+	s.writeFeed(w, r, params.Days, "application/rss+xml; charset=UTF-8", (*quote.Feed).WriteRSS)
+}
+
+// GET feed.atom serves the Atom feed of the quotes of the day
+func (s *Server) GetAtomFeed(w http.ResponseWriter, r *http.Request, params GetAtomFeedParams) {
+	s.writeFeed(w, r, params.Days, "application/atom+xml; charset=UTF-8", (*quote.Feed).WriteAtom)
+}
+
+// writeFeed serves the feed of the quotes of the given number of days
+// written by write
+func (s *Server) writeFeed(w http.ResponseWriter, r *http.Request, days *Days, contentType string, write func(*quote.Feed, io.Writer) error) {
+	allowAnyOrigin(w)
+	n := defaultFeedDays
+	if days != nil {
+		n = *days
+	}
+	if n < 1 || n > maxPageLimit {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("days must be between 1 and %d", maxPageLimit))
+		return
+	}
+
+	now := s.now().In(s.location)
+	feed, err := quote.NewFeed(s.store, s.baseURL(w, r), now, n)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	var buf bytes.Buffer
+	if err := write(feed, &buf); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	serveDailyContent(w, r, now, feed.Updated(), contentType, buf.Bytes())
+}
+
+const (
+	defaultCalendarDays = 30
+	maxCalendarDays     = 366
+)
+
+// GET calendar.ics serves the calendar of the quotes of the upcoming days
+func (s *Server) GetCalendar(w http.ResponseWriter, r *http.Request, params GetCalendarParams) {
+	allowAnyOrigin(w)
+	days := defaultCalendarDays
+	if params.Days != nil {
+		days = *params.Days
+	}
+	if days < 1 || days > maxCalendarDays {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("days must be between 1 and %d", maxCalendarDays))
+		return
+	}
+
+	now := s.now().In(s.location)
+	calendar, err := quote.NewCalendar(s.store, s.baseURL(w, r), now, days)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	var buf bytes.Buffer
+	if err := calendar.WriteICS(&buf); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("inline", map[string]string{
+		"filename": "quotaday.ics",
+	}))
+	serveDailyContent(w, r, now, calendar.Created, "text/calendar; charset=UTF-8", buf.Bytes())
+}
+
+// serveDailyContent serves content which changes when the quote of the day
+// rolls over. Clients can make conditional requests, with either its ETag
+// or its modification time.
+func serveDailyContent(w http.ResponseWriter, r *http.Request, now, modtime time.Time, contentType string, content []byte) {
+	h := fnv.New64a()
+	_, _ = h.Write(content)
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("ETag", fmt.Sprintf("\"%x\"", h.Sum64()))
+	setCacheUntilNextDay(w, now)
+	http.ServeContent(w, r, "", modtime, bytes.NewReader(content))
+}
+
+// baseURL returns the URL the Server is reachable at, as configured or as
+// requested by the client, possibly through a trusted proxy. Guessed URLs
+// make the response vary by the headers they are guessed from.
+func (s *Server) baseURL(w http.ResponseWriter, r *http.Request) string {
+	if s.base != "" {
+		return s.base
+	}
+
+	w.Header().Add("Vary", "Host")
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	host := r.Host
+	if s.fromTrustedProxy(r) {
+		w.Header().Add("Vary", "X-Forwarded-Host, X-Forwarded-Proto")
+		if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+			scheme, _, _ = strings.Cut(proto, ",")
+		}
+		if forwarded := r.Header.Get("X-Forwarded-Host"); forwarded != "" {
+			host, _, _ = strings.Cut(forwarded, ",")
 		}
 	}
+	return strings.TrimSpace(scheme) + "://" + strings.TrimSpace(host)
+}
+
+// fromTrustedProxy tells whether r comes from one of the trusted proxies
+func (s *Server) fromTrustedProxy(r *http.Request) bool {
+	addrPort, err := netip.ParseAddrPort(r.RemoteAddr)
+	if err != nil {
+		return false
+	}
+	addr := addrPort.Addr().Unmap()
+	return slices.ContainsFunc(s.proxies, func(p netip.Prefix) bool {
+		return p.Contains(addr)
+	})
+}
+
+// rendering holds the query parameters choosing how quotes are rendered
+type rendering struct {
+	format *Format
+	width  *Width
+	theme  *Theme
+	// offers are the content types which can be served, all the ones
+	// of the registered renderers if nil
+	offers []string
+}
+
+// writeQuote serves q in the requested format or in the one best matching
+// the Accept header
+func (s *Server) writeQuote(w http.ResponseWriter, r *http.Request, q *quote.Quotation, rnd rendering) {
+	offers := rnd.offers
+	if offers == nil {
+		offers = s.renderers.ContentTypes()
+	}
+	contentType, renderer, opts, ok := s.negotiateRenderer(w, r, offers, rnd)
+	if !ok {
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	if err := renderer.RenderQuote(w, q, opts); err != nil {
+		requestLogger(r).Warn("error writing response", "error", err)
+		return
+	}
+	recordServed(r, q)
+}
+
+// writePage serves p in the requested format or in the one best matching
+// the Accept header
+func (s *Server) writePage(w http.ResponseWriter, r *http.Request, p *quote.Page, rnd rendering) {
+	contentType, renderer, opts, ok := s.negotiateRenderer(w, r, s.renderers.PageContentTypes(), rnd)
+	if !ok {
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	if err := renderer.(quote.PageRenderer).RenderPage(w, p, opts); err != nil {
+		requestLogger(r).Warn("error writing response", "error", err)
+	}
+}
 
-	// Default
-	log.Print("No acceptable MIME type found in the \"Accept\" header")
-	w.WriteHeader(http.StatusNotAcceptable)
+// renderOptions returns the options quotes are rendered with, but the base
+// URL, which only HTML pages need
+func renderOptions(rnd rendering) (quote.RenderOptions, error) {
+	var opts quote.RenderOptions
+	if rnd.width != nil {
+		if *rnd.width < 0 {
+			return opts, fmt.Errorf("invalid width %d", *rnd.width)
+		}
+		opts.Width = *rnd.width
+	}
+	if rnd.theme != nil {
+		if !slices.Contains(quote.Themes, string(*rnd.theme)) {
+			return opts, fmt.Errorf("unknown theme %q", *rnd.theme)
+		}
+		opts.Theme = string(*rnd.theme)
+	}
+	return opts, nil
+}
+
+// negotiateRenderer picks the content type among the offered ones, along
+// with its renderer and options: the one of the format requested in the
+// query, if any, or the one best matching the Accept header.
+// When none is acceptable it replies with an error and returns false.
+func (s *Server) negotiateRenderer(w http.ResponseWriter, r *http.Request, offers []string, rnd rendering) (string, quote.Renderer, quote.RenderOptions, bool) {
+	w.Header().Add("Vary", "Accept")
+
+	opts, err := renderOptions(rnd)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return "", nil, opts, false
+	}
+
+	var contentType string
+	var ok bool
+	if rnd.format != nil {
+		if contentType, ok = s.renderers.LookupFormat(string(*rnd.format)); !ok {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown format %q", *rnd.format))
+			return "", nil, opts, false
+		}
+		ok = slices.Contains(offers, contentType)
+	} else {
+		contentType, ok = negotiate(r.Header.Values("Accept"), offers)
+	}
+	if !ok {
+		requestLogger(r).Info("no acceptable media type", "accept", r.Header.Values("Accept"))
+		mediaTypes := make([]string, 0, len(offers))
+		for _, offer := range offers {
+			mt, _, _ := mime.ParseMediaType(offer)
+			mediaTypes = append(mediaTypes, mt)
+		}
+		writeError(w, http.StatusNotAcceptable, fmt.Sprintf("no acceptable representation, available: %s", strings.Join(mediaTypes, ", ")))
+		return "", nil, opts, false
+	}
+	if mediaType, _, _ := mime.ParseMediaType(contentType); mediaType == "text/html" {
+		// Only HTML pages link to the quotes, other representations must
+		// not vary by the headers the base URL is guessed from
+		opts.BaseURL = s.baseURL(w, r)
+	}
+	renderer, _ := s.renderers.Lookup(contentType)
+	return contentType, renderer, opts, true
 }
 
 // POST quote adds a quote to the available ones
 func (s *Server) PostQuote(w http.ResponseWriter, r *http.Request) {
-	log.Print(getRemoteHostInfo(r))
-
 	var newQuote quote.Quotation
 	if err := json.NewDecoder(r.Body).Decode(&newQuote); err != nil {
-		log.Printf("json decode failed: %s", err)
-		w.WriteHeader(http.StatusBadRequest)
-		_, _ = w.Write([]byte("could not read request body"))
+		requestLogger(r).Info("cannot decode request body", "error", err)
+		writeError(w, http.StatusBadRequest, "could not read request body")
 		return
 	}
 
-	if err := s.qb.AddQuote(newQuote); err != nil {
-		log.Printf("QuoteBook Add failed: %s", err)
-		w.WriteHeader(http.StatusInsufficientStorage)
-		_, _ = w.Write([]byte(err.Error()))
+	added, err := s.store.AddQuote(newQuote)
+	if err != nil {
+		requestLogger(r).Warn("cannot add quote", "error", err)
+		recordAddFailure(r, err)
+		writeStoreError(w, err)
 		return
 	}
 
-	w.WriteHeader(http.StatusCreated)
-	log.Printf("Quote added:\n%q\n%q", newQuote.Quote, newQuote.Author)
-	_ = newQuote.WriteJSON(w)
+	requestLogger(r).Info("quote added", "id", added.ID, "quote", added.Quote, "author", added.Author)
+	writeJSON(w, r, http.StatusCreated, added)
 }
 
-func getRemoteHostInfo(r *http.Request) string {
-	// Sample Headers:
-	// Accept:[*/*]
-	// Accept-Encoding:[gzip, br]
-	// Cdn-Loop:[cloudflare; loops=1]
-	// Cf-Connecting-Ip:[1.2.3.4]
-	// Cf-Ipcountry:[IT]
-	// Cf-Ray:[93804dd5edd859f5-MXP]
-	// Cf-Visitor:[{"scheme":"https"}]
-	// User-Agent:[curl/7.88.1]
-	// X-Forwarded-For:[2.3.4.5]
-	// X-Forwarded-Host:[quote.example.com]
-	// X-Forwarded-Port:[80]
-	// X-Forwarded-Proto:[http]
-	// X-Forwarded-Server:[traefik-32bfd46sce-74c3h]
-	// X-Real-Ip:[2.3.4.5]]
+const (
+	defaultPageLimit = 10
+	maxPageLimit     = 100
+)
 
-	remoteAddr := r.RemoteAddr
-	userAgent := r.Header.Get("User-Agent")
+// GET quotes serves a page of the available quotations
+func (s *Server) ListQuotes(w http.ResponseWriter, r *http.Request, params ListQuotesParams) {
+	allowAnyOrigin(w)
+	offset, limit := 0, defaultPageLimit
+	if params.Offset != nil {
+		offset = *params.Offset
+	}
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+	if offset < 0 {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid offset %d", offset))
+		return
+	}
+	if limit < 1 || limit > maxPageLimit {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", maxPageLimit))
+		return
+	}
+
+	page, err := quote.NewPage(s.store, offset, limit)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
-	// Proxied through Cloudflare?
-	if remote := r.Header.Get("Cf-Connecting-Ip"); remote != "" {
-		remoteAddr = fmt.Sprintf("%s (%s)", remote, r.Header.Get("Cf-Ipcountry"))
-	} else if remote := r.Header.Get("X-Real-Ip"); remote != "" {
-		remoteAddr = remote
-	} else if remote := r.Header.Get("X-Forwarded-For"); remote != "" {
-		remoteAddr = remote
+	w.Header().Set("Link", pageLinks(r.URL, page))
+	s.writePage(w, r, page, rendering{format: params.Format, width: params.Width, theme: params.Theme})
+}
+
+// pageLinks returns the value of the Link header pointing to the pages
+// around page, as defined in RFC 8288
+func pageLinks(u *url.URL, page *quote.Page) string {
+	link := func(offset int, rel string) string {
+		query := u.Query()
+		query.Set("offset", strconv.Itoa(offset))
+		query.Set("limit", strconv.Itoa(page.Limit))
+		target := url.URL{Path: u.Path, RawQuery: query.Encode()}
+		return fmt.Sprintf("<%s>; rel=%q", target.String(), rel)
+	}
+
+	links := []string{link(0, "first")}
+	if page.HasPrev() {
+		links = append(links, link(page.PrevOffset(), "prev"))
 	}
+	if page.HasNext() {
+		links = append(links, link(page.NextOffset(), "next"))
+	}
+	links = append(links, link(page.LastOffset(), "last"))
+	return strings.Join(links, ", ")
+}
+
+// maxImportSize is the maximum size of the body of an import request
+const maxImportSize = 10 << 20
 
-	return fmt.Sprintf("%s %q - %s %s %q", remoteAddr, userAgent, r.Method, r.Proto, r.URL.String())
+// POST quotes:import adds many quotes at once
+func (s *Server) ImportQuotes(w http.ResponseWriter, r *http.Request, params ImportQuotesParams) {
+	var format quote.Format
+	var err error
+	if params.Format != nil {
+		if format, err = quote.ParseFormat(string(*params.Format)); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	} else {
+		var mediaType string
+		mediaType, _, err = mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err == nil {
+			format, err = quote.FormatFromMediaType(mediaType)
+		}
+		if err != nil {
+			writeError(w, http.StatusUnsupportedMediaType, fmt.Sprintf("cannot detect the format of the quotes: %s", err))
+			return
+		}
+	}
+
+	opts := quote.ImportOptions{
+		DryRun:  params.DryRun != nil && *params.DryRun,
+		KeepIDs: params.KeepIds != nil && *params.KeepIds,
+	}
+	body := http.MaxBytesReader(w, r.Body, maxImportSize)
+	report, err := quote.Import(s.store, body, format, opts)
+	if err != nil {
+		requestLogger(r).Warn("cannot import quotes", "error", err)
+		var maxBytesErr *http.MaxBytesError
+		switch {
+		case errors.As(err, &maxBytesErr):
+			writeError(w, http.StatusRequestEntityTooLarge, err.Error())
+		case errors.Is(err, quote.ErrFull):
+			recordAddFailure(r, err)
+			writeError(w, http.StatusInsufficientStorage, err.Error())
+		default:
+			writeError(w, http.StatusBadRequest, err.Error())
+		}
+		return
+	}
+
+	requestLogger(r).Info("quotes imported", "imported", report.Imported, "dry_run", opts.DryRun, "errors", len(report.Errors))
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		requestLogger(r).Warn("error writing import report", "error", err)
+	}
+}
+
+// GET quotes:export serves all the quotes in the requested format
+func (s *Server) ExportQuotes(w http.ResponseWriter, r *http.Request, params ExportQuotesParams) {
+	format := quote.FormatJSON
+	if params.Format != nil {
+		var err error
+		if format, err = quote.ParseFormat(string(*params.Format)); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", format.MediaType()+"; charset=UTF-8")
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+		"filename": "quotes" + format.Extension(),
+	}))
+	// Quotes are streamed: once writing started errors cannot be reported
+	// to the client anymore
+	if err := quote.Export(s.store, w, format); err != nil {
+		requestLogger(r).Error("cannot export quotes", "error", err)
+	}
+}
+
+// GET quotes/search serves the quotations matching a query
+func (s *Server) SearchQuotes(w http.ResponseWriter, r *http.Request, params SearchQuotesParams) {
+	allowAnyOrigin(w)
+	limit := defaultPageLimit
+	if params.Limit != nil {
+		limit = *params.Limit
+	}
+	if limit < 1 || limit > maxPageLimit {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", maxPageLimit))
+		return
+	}
+
+	results, err := s.store.Search(params.Q, limit)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if results == nil {
+		results = []quote.SearchResult{}
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	if err := json.NewEncoder(w).Encode(results); err != nil {
+		requestLogger(r).Warn("error writing search results", "error", err)
+	}
+}
+
+// GET quotes/{id} serves the quotation with the given ID
+func (s *Server) GetQuoteById(w http.ResponseWriter, r *http.Request, id int, params GetQuoteByIdParams) {
+	allowAnyOrigin(w)
+	q, err := s.store.GetQuote(id)
+	if err != nil {
+		writeStoreError(w, err)
+		return
+	}
+
+	s.writeQuote(w, r, q, rendering{format: params.Format, width: params.Width, theme: params.Theme})
+}
+
+// PUT quotes/{id} replaces the quotation with the given ID
+func (s *Server) ReplaceQuote(w http.ResponseWriter, r *http.Request, id int) {
+	var body Quote
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		requestLogger(r).Info("cannot decode request body", "error", err)
+		writeError(w, http.StatusBadRequest, "could not read request body")
+		return
+	}
+	if body.Id != nil && *body.Id != id {
+		writeError(w, http.StatusConflict, fmt.Sprintf("id %d in request body does not match id %d in path", *body.Id, id))
+		return
+	}
+
+	replacement := quote.Quotation{ID: id, Quote: body.Quote}
+	if body.Author != nil {
+		replacement.Author = *body.Author
+	}
+	if body.Tags != nil {
+		replacement.Tags = *body.Tags
+	}
+	// The stored quote is returned by the same call, as others may change
+	// it right after
+	q, err := s.store.PatchQuote(id, func(q *quote.Quotation) error {
+		*q = replacement
+		return nil
+	})
+	if err != nil {
+		requestLogger(r).Warn("cannot update quote", "id", id, "error", err)
+		writeStoreError(w, err)
+		return
+	}
+
+	requestLogger(r).Info("quote replaced", "id", id)
+	writeJSON(w, r, http.StatusOK, q)
+}
+
+// PATCH quotes/{id} updates the given fields of the quotation with the given ID
+func (s *Server) UpdateQuote(w http.ResponseWriter, r *http.Request, id int) {
+	var body QuotePatch
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		requestLogger(r).Info("cannot decode request body", "error", err)
+		writeError(w, http.StatusBadRequest, "could not read request body")
+		return
+	}
+
+	// Fields are changed atomically, not to lose concurrent updates of others
+	q, err := s.store.PatchQuote(id, func(q *quote.Quotation) error {
+		if body.Quote != nil {
+			q.Quote = *body.Quote
+		}
+		if body.Author != nil {
+			q.Author = *body.Author
+		}
+		if body.Tags != nil {
+			q.Tags = *body.Tags
+		}
+		return nil
+	})
+	if err != nil {
+		requestLogger(r).Warn("cannot update quote", "id", id, "error", err)
+		writeStoreError(w, err)
+		return
+	}
+
+	requestLogger(r).Info("quote updated", "id", id)
+	writeJSON(w, r, http.StatusOK, q)
+}
+
+// DELETE quotes/{id} removes the quotation with the given ID
+func (s *Server) DeleteQuote(w http.ResponseWriter, r *http.Request, id int) {
+	if err := s.store.DeleteQuote(id); err != nil {
+		requestLogger(r).Warn("cannot delete quote", "id", id, "error", err)
+		writeStoreError(w, err)
+		return
+	}
+
+	requestLogger(r).Info("quote deleted", "id", id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GET tags serves the tags of the quotations with their usage count
+func (s *Server) ListTags(w http.ResponseWriter, r *http.Request) {
+	allowAnyOrigin(w)
+	tags := s.store.Tags()
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	if err := json.NewEncoder(w).Encode(tags); err != nil {
+		requestLogger(r).Warn("error writing tags", "error", err)
+	}
+}
+
+// allowAnyOrigin lets the scripts of any site read the response of a public
+// read endpoint, e.g., the embed script and the oEmbed consumers
+func allowAnyOrigin(w http.ResponseWriter) {
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+}
+
+// writeJSON replies with the given HTTP status code and q encoded in JSON
+func writeJSON(w http.ResponseWriter, r *http.Request, code int, q *quote.Quotation) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(code)
+	if err := q.WriteJSON(w); err != nil {
+		requestLogger(r).Warn("error writing quote", "error", err)
+	}
+}
+
+// writeError replies with the given HTTP status code and error message
+func writeError(w http.ResponseWriter, code int, msg string) {
+	w.WriteHeader(code)
+	_, _ = w.Write([]byte(msg))
+}
+
+// writeStoreError replies with the HTTP status code matching an error
+// returned by the quote Store
+func writeStoreError(w http.ResponseWriter, err error) {
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, quote.ErrNotFound):
+		code = http.StatusNotFound
+	case errors.Is(err, quote.ErrInvalid):
+		code = http.StatusBadRequest
+	case errors.Is(err, quote.ErrFull):
+		code = http.StatusInsufficientStorage
+	case errors.Is(err, quote.ErrClosed):
+		code = http.StatusServiceUnavailable
+	}
+	writeError(w, code, err.Error())
 }
@@ -19,11 +19,16 @@ package api
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/netip"
+	"reflect"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/fgday/quotaday/pkg/quote"
 )
@@ -76,6 +81,236 @@ func TestGetQuote_Random_HTML(t *testing.T) {
 	}
 }
 
+func TestGetQuote_Negotiation(t *testing.T) {
+	s := NewServer()
+	tests := []struct {
+		accept string
+		code   int
+		ct     string
+		// vary is Host too only for HTML pages, which link to the base URL
+		vary []string
+	}{
+		{"text/html;q=0.9, application/json;q=0.5", http.StatusOK, "text/html", []string{"Accept", "Host"}},
+		{"application/*", http.StatusOK, "application/json", []string{"Accept"}},
+		{"text/*", http.StatusOK, "text/html", []string{"Accept", "Host"}},
+		{"image/png", http.StatusOK, "image/png", []string{"Accept"}},
+		{"image/gif, text/html;q=0", http.StatusNotAcceptable, "", []string{"Accept"}},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", "/quote", nil)
+		req.Header.Set("Accept", tt.accept)
+		w := httptest.NewRecorder()
+		s.GetQuote(w, req, GetQuoteParams{})
+
+		resp := w.Result()
+		resp.Body.Close()
+		if resp.StatusCode != tt.code {
+			t.Errorf("Accept %q: expected %d, got %d", tt.accept, tt.code, resp.StatusCode)
+		}
+		if ct := resp.Header.Get("Content-Type"); tt.ct != "" && !strings.HasPrefix(ct, tt.ct) {
+			t.Errorf("Accept %q: expected %s content-type, got %q", tt.accept, tt.ct, ct)
+		}
+		if vary := resp.Header.Values("Vary"); !reflect.DeepEqual(vary, tt.vary) {
+			t.Errorf("Accept %q: expected Vary %q, got %q", tt.accept, tt.vary, vary)
+		}
+	}
+}
+
+// cardRenderer only renders single quotes
+type cardRenderer struct{}
+
+func (cardRenderer) RenderQuote(w io.Writer, q *quote.Quotation, _ quote.RenderOptions) error {
+	_, err := fmt.Fprintf(w, "[%s]", q.Quote)
+	return err
+}
+
+func TestWithRenderers(t *testing.T) {
+	renderers := quote.NewRegistry()
+	renderers.Register("card", "application/x-card", cardRenderer{})
+	s := NewServer(WithRenderers(renderers))
+	h := HandlerFromMux(s, http.NewServeMux())
+
+	req := httptest.NewRequest("GET", "/quotes/1", nil)
+	req.Header.Set("Accept", "application/x-card")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "application/x-card" {
+		t.Errorf("Expected application/x-card content-type, got %q", ct)
+	}
+	if body := w.Body.String(); body != "[Eat the frog first.]" {
+		t.Errorf("Unexpected body %q", body)
+	}
+
+	// Pages cannot be rendered as cards
+	for _, target := range []string{"/quotes", "/quotes?format=card"} {
+		req = httptest.NewRequest("GET", target, nil)
+		req.Header.Set("Accept", "application/x-card")
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if w.Code != http.StatusNotAcceptable {
+			t.Errorf("%s: expected 406, got %d", target, w.Code)
+		}
+	}
+}
+
+func TestGetQuote_Format(t *testing.T) {
+	h := HandlerFromMux(NewServer(), http.NewServeMux())
+	tests := []struct {
+		target string
+		accept string
+		code   int
+		ct     string
+		body   string
+	}{
+		{"/quotes/1", "text/plain", http.StatusOK, "text/plain", "Eat the frog first.\n— Brian Tracy\n"},
+		{"/quotes/0?width=20", "text/plain", http.StatusOK, "text/plain", "Start before you are\nready. Don't\nprepare, begin.\n— Mel Robbins\n"},
+		{"/quotes/1", "text/markdown", http.StatusOK, "text/markdown", "> Eat the frog first.\n>\n> — Brian Tracy\n"},
+		{"/quotes/1?format=text", "application/json", http.StatusOK, "text/plain", "Eat the frog first.\n— Brian Tracy\n"},
+		{"/quote?id=1&format=markdown", "", http.StatusOK, "text/markdown", "> Eat the frog first.\n>\n> — Brian Tracy\n"},
+		{"/quotes?limit=2&format=text", "", http.StatusOK, "text/plain", "Start before you are ready. Don't prepare, begin.\n— Mel Robbins\n\nEat the frog first.\n— Brian Tracy\n"},
+		{"/quotes/1?format=pdf", "", http.StatusBadRequest, "", ""},
+		{"/quotes/1?format=text&width=-1", "", http.StatusBadRequest, "", ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.target, nil)
+		if tt.accept != "" {
+			req.Header.Set("Accept", tt.accept)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if w.Code != tt.code {
+			t.Errorf("%s: expected %d, got %d", tt.target, tt.code, w.Code)
+			continue
+		}
+		if tt.code != http.StatusOK {
+			continue
+		}
+		if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, tt.ct) {
+			t.Errorf("%s: expected %s content-type, got %q", tt.target, tt.ct, ct)
+		}
+		if body := w.Body.String(); body != tt.body {
+			t.Errorf("%s: expected body %q, got %q", tt.target, tt.body, body)
+		}
+	}
+}
+
+func TestGetQuote_Theme(t *testing.T) {
+	h := HandlerFromMux(NewServer(), http.NewServeMux())
+	tests := []struct {
+		target string
+		code   int
+		body   string
+	}{
+		{"/quotes/1", http.StatusOK, `<body class="theme-auto">`},
+		{"/quotes/1?theme=dark", http.StatusOK, `<body class="theme-dark">`},
+		{"/quote?mode=daily&theme=light", http.StatusOK, `<body class="theme-light">`},
+		{"/quotes?limit=1&theme=dark", http.StatusOK, "offset=1&limit=1&theme=dark"},
+		{"/quotes/1?theme=sepia", http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.target, nil)
+		req.Header.Set("Accept", "text/html")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if w.Code != tt.code {
+			t.Errorf("%s: expected %d, got %d", tt.target, tt.code, w.Code)
+			continue
+		}
+		if body := w.Body.String(); !strings.Contains(body, tt.body) {
+			t.Errorf("%s: expected body containing %q, got %q", tt.target, tt.body, body)
+		}
+	}
+}
+
+// testProxies are the trusted proxies, including the address requests
+// made by httptest.NewRequest come from
+var testProxies = []netip.Prefix{netip.MustParsePrefix("192.0.2.0/24")}
+
+func TestGetQuote_Metadata(t *testing.T) {
+	h := HandlerFromMux(NewServer(WithTrustedProxies(testProxies)), http.NewServeMux())
+	req := httptest.NewRequest("GET", "/quote?id=1", nil)
+	req.Header.Set("Accept", "text/html")
+	req.Header.Set("X-Forwarded-Proto", "https")
+	req.Header.Set("X-Forwarded-Host", "quote.example.com")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	for _, want := range []string{
+		`<title>Brian Tracy - Quotaday</title>`,
+		`<meta name="description" content="Eat the frog first.">`,
+		`<link rel="canonical" href="https://quote.example.com/quotes/1">`,
+		`<meta property="og:url" content="https://quote.example.com/quotes/1">`,
+		`<meta property="og:image" content="https://quote.example.com/quote/image?id=1&amp;format=png">`,
+		`<meta name="twitter:image" content="https://quote.example.com/quote/image?id=1&amp;format=png">`,
+	} {
+		if !strings.Contains(w.Body.String(), want) {
+			t.Errorf("Expected %q in page:\n%s", want, w.Body.String())
+		}
+	}
+}
+
+func TestGetQuoteImage(t *testing.T) {
+	h := HandlerFromMux(NewServer(), http.NewServeMux())
+	tests := []struct {
+		target string
+		accept string
+		code   int
+		ct     string
+	}{
+		{"/quote/image?id=1", "", http.StatusOK, "image/png"},
+		{"/quote/image?id=1", "*/*", http.StatusOK, "image/png"},
+		{"/quote/image?id=1", "image/svg+xml", http.StatusOK, "image/svg+xml"},
+		{"/quote/image?mode=daily&format=svg", "", http.StatusOK, "image/svg+xml"},
+		{"/quote/image?tag=action", "text/html,image/*;q=0.5", http.StatusOK, "image/png"},
+		{"/quote/image?id=1", "text/html", http.StatusNotAcceptable, ""},
+		{"/quote/image?id=1&format=json", "", http.StatusNotAcceptable, ""},
+		{"/quote/image?id=100", "", http.StatusNotFound, ""},
+		{"/quote?id=1", "image/png", http.StatusOK, "image/png"},
+		{"/quotes/1?format=svg", "", http.StatusOK, "image/svg+xml"},
+		{"/quotes?format=png", "", http.StatusNotAcceptable, ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", tt.target, nil)
+		if tt.accept != "" {
+			req.Header.Set("Accept", tt.accept)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if w.Code != tt.code {
+			t.Errorf("%s (Accept %q): expected %d, got %d", tt.target, tt.accept, tt.code, w.Code)
+			continue
+		}
+		if tt.code != http.StatusOK {
+			continue
+		}
+		if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, tt.ct) {
+			t.Errorf("%s (Accept %q): expected %s content-type, got %q", tt.target, tt.accept, tt.ct, ct)
+		}
+		if tt.ct == "image/png" && !bytes.HasPrefix(w.Body.Bytes(), []byte("\x89PNG")) {
+			t.Errorf("%s: expected a PNG image", tt.target)
+		}
+	}
+}
+
+func TestGetQuoteImage_NoCards(t *testing.T) {
+	renderers := &quote.Registry{}
+	renderers.Register("json", "application/json; charset=UTF-8", jsonOnly{})
+	h := HandlerFromMux(NewServer(WithRenderers(renderers)), http.NewServeMux())
+	resp := serveRequest(t, h, "GET", "/quote/image?id=1", "")
+	if resp.StatusCode != http.StatusNotAcceptable {
+		t.Errorf("Expected 406 without image renderers, got %d", resp.StatusCode)
+	}
+}
+
+// jsonOnly renders quotes in JSON
+type jsonOnly struct{}
+
+func (jsonOnly) RenderQuote(w io.Writer, q *quote.Quotation, _ quote.RenderOptions) error {
+	return q.WriteJSON(w)
+}
+
 func TestGetQuote_ByID(t *testing.T) {
 	s := NewServer()
 	req := httptest.NewRequest("GET", "/quote?id=0", nil)
@@ -108,12 +343,12 @@ func TestGetQuote_BadID(t *testing.T) {
 
 	resp := w.Result()
 	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusBadRequest {
-		t.Errorf("Expected 400, got %d", resp.StatusCode)
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404, got %d", resp.StatusCode)
 	}
 	body, _ := io.ReadAll(resp.Body)
-	if !strings.Contains(string(body), "out of bounds") {
-		t.Errorf("Expected error message about out of bounds, got %q", string(body))
+	if !strings.Contains(string(body), "not found") {
+		t.Errorf("Expected error message about not found, got %q", string(body))
 	}
 }
 
@@ -135,9 +370,25 @@ func TestPostQuote_Success(t *testing.T) {
 	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
 		t.Errorf("Failed to decode JSON: %v", err)
 	}
-	if got != q {
+	if got.Quote != q.Quote || got.Author != q.Author {
 		t.Errorf("Expected %+v, got %+v", q, got)
 	}
+
+	// The returned id must identify the new quote
+	req = httptest.NewRequest("GET", fmt.Sprintf("/quote?id=%d", got.ID), nil)
+	req.Header.Set("Accept", "application/json")
+	w = httptest.NewRecorder()
+	s.GetQuote(w, req, GetQuoteParams{Id: &got.ID})
+
+	resp = w.Result()
+	defer resp.Body.Close()
+	var fetched quote.Quotation
+	if err := json.NewDecoder(resp.Body).Decode(&fetched); err != nil {
+		t.Errorf("Failed to decode JSON: %v", err)
+	}
+	if !reflect.DeepEqual(fetched, got) {
+		t.Errorf("Expected %+v, got %+v", got, fetched)
+	}
 }
 
 func TestPostQuote_BadBody(t *testing.T) {
@@ -183,3 +434,637 @@ func TestPostQuote_StorageFull(t *testing.T) {
 		t.Errorf("Expected error message about full, got %q", string(bodyBytes))
 	}
 }
+
+func TestNewServer_WithStore(t *testing.T) {
+	qb := quote.New()
+	q := quote.Quotation{Quote: "Only one", Author: "Tester"}
+	added, err := qb.AddQuote(q)
+	if err != nil {
+		t.Fatalf("AddQuote failed: %v", err)
+	}
+	s := NewServer(WithStore(qb))
+	req := httptest.NewRequest("GET", "/quote", nil)
+	req.Header.Set("Accept", "application/json")
+	w := httptest.NewRecorder()
+	s.GetQuote(w, req, GetQuoteParams{})
+
+	resp := w.Result()
+	defer resp.Body.Close()
+	var got quote.Quotation
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatalf("Failed to decode JSON: %v", err)
+	}
+	if !reflect.DeepEqual(got, *added) {
+		t.Errorf("Expected %+v, got %+v", *added, got)
+	}
+}
+
+func serveRequest(t *testing.T, h http.Handler, method, target, body string) *http.Response {
+	t.Helper()
+	var r io.Reader
+	if body != "" {
+		r = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, target, r)
+	req.Header.Set("Accept", "application/json")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	resp := w.Result()
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestGetQuoteById(t *testing.T) {
+	h := HandlerFromMux(NewServer(), http.NewServeMux())
+
+	resp := serveRequest(t, h, "GET", "/quotes/1", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", resp.StatusCode)
+	}
+	var q quote.Quotation
+	if err := json.NewDecoder(resp.Body).Decode(&q); err != nil {
+		t.Fatalf("Failed to decode JSON: %v", err)
+	}
+	if q.ID != 1 {
+		t.Errorf("Expected quote with id 1, got %+v", q)
+	}
+
+	resp = serveRequest(t, h, "GET", "/quotes/999", "")
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404, got %d", resp.StatusCode)
+	}
+}
+
+func TestReplaceQuote(t *testing.T) {
+	h := HandlerFromMux(NewServer(), http.NewServeMux())
+
+	resp := serveRequest(t, h, "PUT", "/quotes/2", `{"quote": "Replaced", "author": "Tester"}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", resp.StatusCode)
+	}
+	want := quote.Quotation{ID: 2, Quote: "Replaced", Author: "Tester"}
+	resp = serveRequest(t, h, "GET", "/quotes/2", "")
+	var got quote.Quotation
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatalf("Failed to decode JSON: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+
+	tests := []struct {
+		name   string
+		target string
+		body   string
+		status int
+	}{
+		{"conflicting id", "/quotes/2", `{"id": 3, "quote": "Replaced"}`, http.StatusConflict},
+		{"not found", "/quotes/999", `{"quote": "Replaced"}`, http.StatusNotFound},
+		{"bad body", "/quotes/2", `not-json`, http.StatusBadRequest},
+		{"bad id", "/quotes/two", `{"quote": "Replaced"}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := serveRequest(t, h, "PUT", tt.target, tt.body)
+			if resp.StatusCode != tt.status {
+				t.Errorf("Expected %d, got %d", tt.status, resp.StatusCode)
+			}
+		})
+	}
+}
+
+// deletingStore is a Store where quotes are deleted by others right after
+// being changed
+type deletingStore struct {
+	*quote.QuoteBook
+}
+
+func (s deletingStore) PatchQuote(id int, patch func(*quote.Quotation) error) (*quote.Quotation, error) {
+	q, err := s.QuoteBook.PatchQuote(id, patch)
+	if err == nil {
+		_ = s.DeleteQuote(id)
+	}
+	return q, err
+}
+
+func (s deletingStore) UpdateQuote(id int, q quote.Quotation) error {
+	_, err := s.PatchQuote(id, func(stored *quote.Quotation) error {
+		*stored = q
+		return nil
+	})
+	return err
+}
+
+func TestReplaceQuote_Concurrent(t *testing.T) {
+	qb := quote.New()
+	qb.FillExample()
+	h := HandlerFromMux(NewServer(WithStore(deletingStore{qb})), http.NewServeMux())
+
+	// The reply is the quote as replaced, not as changed by others
+	resp := serveRequest(t, h, "PUT", "/quotes/2", `{"quote": "Replaced", "tags": ["B", "a"]}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", resp.StatusCode)
+	}
+	var got quote.Quotation
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatalf("Failed to decode JSON: %v", err)
+	}
+	if want := (quote.Quotation{ID: 2, Quote: "Replaced", Tags: []string{"b", "a"}}); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+}
+
+func TestUpdateQuote(t *testing.T) {
+	h := HandlerFromMux(NewServer(), http.NewServeMux())
+
+	resp := serveRequest(t, h, "PATCH", "/quotes/1", `{"author": "Somebody else"}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", resp.StatusCode)
+	}
+	var got quote.Quotation
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatalf("Failed to decode JSON: %v", err)
+	}
+	want := quote.Quotation{ID: 1, Quote: "Eat the frog first.", Author: "Somebody else", Tags: []string{"productivity"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+
+	resp = serveRequest(t, h, "PATCH", "/quotes/999", `{"author": "Nobody"}`)
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404, got %d", resp.StatusCode)
+	}
+}
+
+func TestDeleteQuote(t *testing.T) {
+	h := HandlerFromMux(NewServer(), http.NewServeMux())
+
+	resp := serveRequest(t, h, "DELETE", "/quotes/0", "")
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("Expected 204, got %d", resp.StatusCode)
+	}
+	for _, method := range []string{"GET", "DELETE"} {
+		resp = serveRequest(t, h, method, "/quotes/0", "")
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("%s after delete: expected 404, got %d", method, resp.StatusCode)
+		}
+	}
+}
+
+func TestListQuotes(t *testing.T) {
+	h := HandlerFromMux(NewServer(), http.NewServeMux())
+
+	resp := serveRequest(t, h, "GET", "/quotes?offset=2&limit=2", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", resp.StatusCode)
+	}
+	var page quote.Page
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		t.Fatalf("Failed to decode JSON: %v", err)
+	}
+	if page.Total != 6 || page.Offset != 2 || page.Limit != 2 || len(page.Quotes) != 2 {
+		t.Errorf("Unexpected page: %+v", page)
+	}
+
+	link := resp.Header.Get("Link")
+	for _, want := range []string{
+		`</quotes?limit=2&offset=0>; rel="first"`,
+		`</quotes?limit=2&offset=0>; rel="prev"`,
+		`</quotes?limit=2&offset=4>; rel="next"`,
+		`</quotes?limit=2&offset=4>; rel="last"`,
+	} {
+		if !strings.Contains(link, want) {
+			t.Errorf("Expected %q in Link header %q", want, link)
+		}
+	}
+}
+
+func TestListQuotes_Defaults(t *testing.T) {
+	h := HandlerFromMux(NewServer(), http.NewServeMux())
+
+	resp := serveRequest(t, h, "GET", "/quotes", "")
+	var page quote.Page
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+		t.Fatalf("Failed to decode JSON: %v", err)
+	}
+	if page.Offset != 0 || page.Limit != defaultPageLimit || len(page.Quotes) != 6 {
+		t.Errorf("Unexpected page: %+v", page)
+	}
+	if link := resp.Header.Get("Link"); strings.Contains(link, `rel="next"`) {
+		t.Errorf("Unexpected next link in %q", link)
+	}
+}
+
+func TestListQuotes_BadParams(t *testing.T) {
+	h := HandlerFromMux(NewServer(), http.NewServeMux())
+
+	for _, target := range []string{"/quotes?offset=-1", "/quotes?limit=0", "/quotes?limit=1000", "/quotes?limit=x"} {
+		resp := serveRequest(t, h, "GET", target, "")
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", target, resp.StatusCode)
+		}
+	}
+}
+
+func TestListQuotes_HTML(t *testing.T) {
+	h := HandlerFromMux(NewServer(), http.NewServeMux())
+
+	req := httptest.NewRequest("GET", "/quotes", nil)
+	req.Header.Set("Accept", "text/html")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if ct := w.Header().Get("Content-Type"); !strings.Contains(ct, "text/html") {
+		t.Errorf("Expected text/html content-type, got %q", ct)
+	}
+	if !strings.Contains(w.Body.String(), "Eat the frog first.") {
+		t.Errorf("Expected quotes in HTML output, got %q", w.Body.String())
+	}
+}
+
+func TestGetDailyQuote(t *testing.T) {
+	loc := time.FixedZone("UTC+1", 60*60)
+	s := NewServer(WithLocation(loc))
+	s.now = func() time.Time { return time.Date(2025, 3, 14, 23, 30, 0, 0, time.UTC) }
+	h := HandlerFromMux(s, http.NewServeMux())
+
+	resp := serveRequest(t, h, "GET", "/quote/today", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", resp.StatusCode)
+	}
+	var daily quote.Quotation
+	if err := json.NewDecoder(resp.Body).Decode(&daily); err != nil {
+		t.Fatalf("Failed to decode JSON: %v", err)
+	}
+	// 23:30 UTC is already March 15th in UTC+1
+	want, _ := quote.DailyQuotation(s.store, time.Date(2025, 3, 15, 12, 0, 0, 0, loc))
+	if !reflect.DeepEqual(daily, *want) {
+		t.Errorf("Expected %+v, got %+v", *want, daily)
+	}
+	if cc := resp.Header.Get("Cache-Control"); cc != "public, max-age=84600" {
+		t.Errorf("Unexpected Cache-Control %q", cc)
+	}
+	if exp := resp.Header.Get("Expires"); exp != "Sat, 15 Mar 2025 23:00:00 GMT" {
+		t.Errorf("Unexpected Expires %q", exp)
+	}
+
+	// mode=daily returns the same quote
+	resp = serveRequest(t, h, "GET", "/quote?mode=daily", "")
+	var got quote.Quotation
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatalf("Failed to decode JSON: %v", err)
+	}
+	if !reflect.DeepEqual(got, daily) {
+		t.Errorf("Expected %+v, got %+v", daily, got)
+	}
+	if resp.Header.Get("Expires") == "" {
+		t.Error("Expected Expires header with mode=daily")
+	}
+}
+
+func TestGetQuote_Filtered(t *testing.T) {
+	h := HandlerFromMux(NewServer(), http.NewServeMux())
+
+	for i := 0; i < 10; i++ {
+		resp := serveRequest(t, h, "GET", "/quote?tag=leadership", "")
+		var q quote.Quotation
+		if err := json.NewDecoder(resp.Body).Decode(&q); err != nil {
+			t.Fatalf("Failed to decode JSON: %v", err)
+		}
+		if !q.HasTag("leadership") {
+			t.Errorf("Expected a quote tagged leadership, got %+v", q)
+		}
+	}
+
+	resp := serveRequest(t, h, "GET", "/quote?author=Brian+Tracy", "")
+	var q quote.Quotation
+	if err := json.NewDecoder(resp.Body).Decode(&q); err != nil {
+		t.Fatalf("Failed to decode JSON: %v", err)
+	}
+	if q.Author != "Brian Tracy" {
+		t.Errorf("Expected a quote by Brian Tracy, got %+v", q)
+	}
+
+	resp = serveRequest(t, h, "GET", "/quote?tag=missing", "")
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected 404 for a missing tag, got %d", resp.StatusCode)
+	}
+}
+
+func TestListTags(t *testing.T) {
+	h := HandlerFromMux(NewServer(), http.NewServeMux())
+
+	resp := serveRequest(t, h, "POST", "/quote", `{"quote": "Tagged", "tags": ["Leadership", "new"]}`)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d", resp.StatusCode)
+	}
+
+	resp = serveRequest(t, h, "GET", "/tags", "")
+	var tags []quote.TagCount
+	if err := json.NewDecoder(resp.Body).Decode(&tags); err != nil {
+		t.Fatalf("Failed to decode JSON: %v", err)
+	}
+	if len(tags) == 0 || tags[0] != (quote.TagCount{Tag: "action", Count: 2}) {
+		t.Errorf("Unexpected tags %v", tags)
+	}
+	if !slices.Contains(tags, quote.TagCount{Tag: "leadership", Count: 2}) ||
+		!slices.Contains(tags, quote.TagCount{Tag: "new", Count: 1}) {
+		t.Errorf("Expected tags of the new quote in %v", tags)
+	}
+}
+
+func TestSearchQuotes(t *testing.T) {
+	h := HandlerFromMux(NewServer(), http.NewServeMux())
+
+	resp := serveRequest(t, h, "GET", "/quotes/search?q=Frog", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", resp.StatusCode)
+	}
+	var results []quote.SearchResult
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		t.Fatalf("Failed to decode JSON: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != 1 || !strings.Contains(results[0].Snippet, "<mark>frog</mark>") {
+		t.Errorf("Unexpected results %+v", results)
+	}
+
+	resp = serveRequest(t, h, "GET", "/quotes/search?q=nothing", "")
+	body, _ := io.ReadAll(resp.Body)
+	if strings.TrimSpace(string(body)) != "[]" {
+		t.Errorf("Expected empty list, got %q", body)
+	}
+
+	for _, target := range []string{"/quotes/search", "/quotes/search?q=", "/quotes/search?q=frog&limit=0"} {
+		resp := serveRequest(t, h, "GET", target, "")
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", target, resp.StatusCode)
+		}
+	}
+}
+
+func TestImportQuotes(t *testing.T) {
+	s := NewServer()
+	h := HandlerFromMux(s, http.NewServeMux())
+
+	csv := "quote,author,tags\nFirst,A,x\n,B,\nSecond,C,\"x,y\"\n"
+	req := httptest.NewRequest("POST", "/quotes:import", strings.NewReader(csv))
+	req.Header.Set("Content-Type", "text/csv; charset=utf-8")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var report quote.ImportReport
+	if err := json.NewDecoder(w.Body).Decode(&report); err != nil {
+		t.Fatalf("Failed to decode JSON: %v", err)
+	}
+	if report.Imported != 2 || len(report.Errors) != 1 || report.Errors[0].Line != 3 {
+		t.Errorf("Unexpected report %+v", report)
+	}
+	if count := s.store.Count(); count != 8 {
+		t.Errorf("Expected 8 quotes, got %d", count)
+	}
+
+	// Dry run with explicit format
+	resp := serveRequest(t, h, "POST", "/quotes:import?format=jsonl&dry_run=true", `{"quote": "Third"}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", resp.StatusCode)
+	}
+	if count := s.store.Count(); count != 8 {
+		t.Errorf("Expected no quotes added in a dry run, got %d", count)
+	}
+
+	// Restoring a backup keeps the IDs
+	resp = serveRequest(t, h, "POST", "/quotes:import?format=json&keep_ids=true", `[{"id": 42, "quote": "Kept"}]`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", resp.StatusCode)
+	}
+	if resp := serveRequest(t, h, "GET", "/quotes/42", ""); resp.StatusCode != http.StatusOK {
+		t.Errorf("Expected the quote imported with ID 42, got %d", resp.StatusCode)
+	}
+	if count := s.store.Count(); count != 9 {
+		t.Errorf("Expected 9 quotes, got %d", count)
+	}
+
+	tests := []struct {
+		name        string
+		target      string
+		contentType string
+		body        string
+		status      int
+	}{
+		{"unknown media type", "/quotes:import", "application/xml", "<quote/>", http.StatusUnsupportedMediaType},
+		{"unknown format", "/quotes:import?format=xml", "", "<quote/>", http.StatusBadRequest},
+		{"malformed", "/quotes:import?format=json", "", "{", http.StatusBadRequest},
+		{"too large", "/quotes:import?format=fortune", "", strings.Repeat("x", maxImportSize+1), http.StatusRequestEntityTooLarge},
+		{"full", "/quotes:import?format=json", "", `[` + strings.Repeat(`{"quote": "Q"},`, 20) + `{"quote": "Q"}]`, http.StatusInsufficientStorage},
+		{"full dry run", "/quotes:import?format=json&dry_run=true", "", `[` + strings.Repeat(`{"quote": "Q"},`, 20) + `{"quote": "Q"}]`, http.StatusInsufficientStorage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", tt.target, strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+			if w.Code != tt.status {
+				t.Errorf("Expected %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestExportQuotes(t *testing.T) {
+	h := HandlerFromMux(NewServer(), http.NewServeMux())
+
+	resp := serveRequest(t, h, "GET", "/quotes:export", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		t.Errorf("Expected application/json content-type, got %q", ct)
+	}
+	if cd := resp.Header.Get("Content-Disposition"); cd != "attachment; filename=quotes.json" {
+		t.Errorf("Unexpected Content-Disposition %q", cd)
+	}
+	var quotes []quote.Quotation
+	if err := json.NewDecoder(resp.Body).Decode(&quotes); err != nil {
+		t.Fatalf("Failed to decode JSON: %v", err)
+	}
+	if len(quotes) != 6 {
+		t.Errorf("Expected 6 quotes, got %d", len(quotes))
+	}
+
+	resp = serveRequest(t, h, "GET", "/quotes:export?format=markdown", "")
+	body, _ := io.ReadAll(resp.Body)
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/markdown") {
+		t.Errorf("Expected text/markdown content-type, got %q", ct)
+	}
+	if !strings.Contains(string(body), "> Eat the frog first.\n>\n> — Brian Tracy\n") {
+		t.Errorf("Unexpected Markdown export %q", body)
+	}
+
+	resp = serveRequest(t, h, "GET", "/quotes:export?format=xml", "")
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an unknown format, got %d", resp.StatusCode)
+	}
+}
+
+func TestFeeds(t *testing.T) {
+	s := NewServer(WithLocation(time.UTC), WithTrustedProxies(testProxies))
+	s.now = func() time.Time { return time.Date(2025, 3, 15, 10, 0, 0, 0, time.UTC) }
+	h := HandlerFromMux(s, http.NewServeMux())
+
+	for target, ct := range map[string]string{
+		"/feed.rss":  "application/rss+xml",
+		"/feed.atom": "application/atom+xml",
+	} {
+		req := httptest.NewRequest("GET", target+"?days=3", nil)
+		req.Header.Set("X-Forwarded-Proto", "https")
+		req.Header.Set("X-Forwarded-Host", "quote.example.com")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d", target, w.Code)
+		}
+		if got := w.Header().Get("Content-Type"); !strings.HasPrefix(got, ct) {
+			t.Errorf("%s: expected %s content-type, got %q", target, ct, got)
+		}
+		if got := w.Header().Get("Last-Modified"); got != "Sat, 15 Mar 2025 00:00:00 GMT" {
+			t.Errorf("%s: unexpected Last-Modified %q", target, got)
+		}
+		body := w.Body.String()
+		if n := strings.Count(body, "tag:quote.example.com,2025-03-"); n != 3 {
+			t.Errorf("%s: expected 3 entries, got %d", target, n)
+		}
+		if !strings.Contains(body, "https://quote.example.com/quotes/") {
+			t.Errorf("%s: expected links to the quotes, got %s", target, body)
+		}
+
+		etag := w.Header().Get("ETag")
+		if etag == "" {
+			t.Fatalf("%s: expected an ETag", target)
+		}
+		for header, value := range map[string]string{
+			"If-None-Match":     etag,
+			"If-Modified-Since": "Sat, 15 Mar 2025 08:00:00 GMT",
+		} {
+			req := httptest.NewRequest("GET", target+"?days=3", nil)
+			req.Header.Set("X-Forwarded-Proto", "https")
+			req.Header.Set("X-Forwarded-Host", "quote.example.com")
+			req.Header.Set(header, value)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+			if w.Code != http.StatusNotModified {
+				t.Errorf("%s with %s: expected 304, got %d", target, header, w.Code)
+			}
+		}
+
+		// The quote of the next day changes the feed
+		req = httptest.NewRequest("GET", target+"?days=3", nil)
+		req.Header.Set("If-Modified-Since", "Fri, 14 Mar 2025 08:00:00 GMT")
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Errorf("%s: expected 200 for an older feed, got %d", target, w.Code)
+		}
+	}
+
+	resp := serveRequest(t, h, "GET", "/feed.rss?days=0", "")
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid days, got %d", resp.StatusCode)
+	}
+}
+
+func TestBaseURL_UntrustedProxy(t *testing.T) {
+	h := HandlerFromMux(NewServer(), http.NewServeMux())
+	req := httptest.NewRequest("GET", "/feed.atom?days=1", nil)
+	req.Host = "quote.example.com"
+	req.Header.Set("X-Forwarded-Proto", "https")
+	req.Header.Set("X-Forwarded-Host", "evil.example.net")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if body := w.Body.String(); strings.Contains(body, "evil.example.net") || !strings.Contains(body, "http://quote.example.com/feed.atom") {
+		t.Errorf("Expected the forwarded headers to be ignored, got %s", body)
+	}
+	if vary := w.Header().Values("Vary"); !slices.Contains(vary, "Host") {
+		t.Errorf("Expected the response to vary by Host, got %q", vary)
+	}
+}
+
+func TestWithBaseURL(t *testing.T) {
+	s := NewServer(WithBaseURL("https://quotes.example.org/"))
+	h := HandlerFromMux(s, http.NewServeMux())
+	resp := serveRequest(t, h, "GET", "/feed.atom?days=1", "")
+	body, _ := io.ReadAll(resp.Body)
+	if !strings.Contains(string(body), `<link href="https://quotes.example.org/feed.atom" rel="self">`) {
+		t.Errorf("Expected links to the base URL, got %s", body)
+	}
+}
+
+func TestGetCalendar(t *testing.T) {
+	s := NewServer(WithLocation(time.UTC), WithBaseURL("https://quote.example.com"))
+	s.now = func() time.Time { return time.Date(2025, 3, 15, 10, 0, 0, 0, time.UTC) }
+	h := HandlerFromMux(s, http.NewServeMux())
+
+	resp := serveRequest(t, h, "GET", "/calendar.ics?days=7", "")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", resp.StatusCode)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "text/calendar; charset=UTF-8" {
+		t.Errorf("Unexpected content-type %q", ct)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	ics := string(body)
+	if n := strings.Count(ics, "BEGIN:VEVENT"); n != 7 {
+		t.Errorf("Expected 7 events, got %d", n)
+	}
+	for _, want := range []string{"DTSTART;VALUE=DATE:20250315", "DTSTART;VALUE=DATE:20250321", "UID:2025-03-15-daily@quote.example.com"} {
+		if !strings.Contains(ics, want) {
+			t.Errorf("Expected %q in calendar:\n%s", want, ics)
+		}
+	}
+
+	resp = serveRequest(t, h, "GET", "/calendar.ics", "")
+	body, _ = io.ReadAll(resp.Body)
+	if n := strings.Count(string(body), "BEGIN:VEVENT"); n != defaultCalendarDays {
+		t.Errorf("Expected %d events by default, got %d", defaultCalendarDays, n)
+	}
+
+	// The calendar does not change during the day
+	req := httptest.NewRequest("GET", "/calendar.ics", nil)
+	req.Header.Set("If-None-Match", resp.Header.Get("ETag"))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusNotModified {
+		t.Errorf("Expected 304, got %d", w.Code)
+	}
+
+	for _, target := range []string{"/calendar.ics?days=0", "/calendar.ics?days=367"} {
+		if resp := serveRequest(t, h, "GET", target, ""); resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", target, resp.StatusCode)
+		}
+	}
+}
+
+func TestAllowAnyOrigin(t *testing.T) {
+	h := HandlerFromMux(NewServer(WithBaseURL("https://quote.example.com")), http.NewServeMux())
+	for _, target := range []string{
+		"/quote", "/quote?tag=missing", "/quote/today", "/quotes", "/quotes/1",
+		"/quotes/search?q=life", "/tags", "/feed.rss", "/feed.atom", "/calendar.ics",
+		"/oembed?url=https://quote.example.com/embed",
+	} {
+		resp := serveRequest(t, h, "GET", target, "")
+		if origin := resp.Header.Get("Access-Control-Allow-Origin"); origin != "*" {
+			t.Errorf("%s: expected any origin allowed, got %q", target, origin)
+		}
+	}
+
+	// Only reading is allowed to other sites
+	for _, target := range []string{"/quotes:export", "/healthz"} {
+		resp := serveRequest(t, h, "GET", target, "")
+		if origin := resp.Header.Get("Access-Control-Allow-Origin"); origin != "" {
+			t.Errorf("%s: expected no origin allowed, got %q", target, origin)
+		}
+	}
+}
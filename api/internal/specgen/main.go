@@ -0,0 +1,167 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Command specgen lists the media types of the quote renderers in the
+// responses of the OpenAPI specification, so that every registered
+// renderer is advertised.
+//
+// The content of the responses is replaced between lines like:
+//
+//	# BEGIN renderers quote
+//	# END renderers
+//
+// listing the renderers of single quotes ("quote"), of images of quotes
+// ("image"), of pages of quotes ("page") or their names ("formats"),
+// indented as the BEGIN line.
+//
+// Usage:
+//
+//	specgen openapi.yaml
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"mime"
+	"os"
+	"strings"
+
+	"github.com/fgday/quotaday/pkg/quote"
+)
+
+const (
+	beginMarker = "# BEGIN renderers "
+	endMarker   = "# END renderers"
+)
+
+// schemas are the JSON schemas of the structured representations
+var schemas = map[string]map[string]string{
+	"quote": {"application/json": "#/components/schemas/Quote"},
+	"page":  {"application/json": "#/components/schemas/QuoteList"},
+}
+
+func main() {
+	if len(os.Args) != 2 {
+		log.Fatal("usage: specgen FILE")
+	}
+	path := os.Args[1]
+
+	spec, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	updated, err := generate(spec, quote.NewRegistry())
+	if err != nil {
+		log.Fatalf("%s: %s", path, err)
+	}
+	if bytes.Equal(spec, updated) {
+		return
+	}
+	if err := os.WriteFile(path, updated, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// generate returns spec with the content between the markers replaced
+// by the media types of the renderers in registry
+func generate(spec []byte, registry *quote.Registry) ([]byte, error) {
+	var out bytes.Buffer
+	lines := strings.SplitAfter(string(spec), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		out.WriteString(line)
+
+		trimmed := strings.TrimSpace(line)
+		kind, ok := strings.CutPrefix(trimmed, beginMarker)
+		if !ok {
+			continue
+		}
+		// Skip the previously generated content
+		end := i + 1
+		for end < len(lines) && strings.TrimSpace(lines[end]) != endMarker {
+			end++
+		}
+		if end == len(lines) {
+			return nil, fmt.Errorf("line %d: missing %q", i+1, endMarker)
+		}
+
+		indent := line[:len(line)-len(strings.TrimLeft(line, " "))]
+		switch kind {
+		case "formats":
+			for _, name := range registry.Formats() {
+				fmt.Fprintf(&out, "%s- %s\n", indent, name)
+			}
+		case "quote":
+			if err := writeContent(&out, indent, kind, registry.ContentTypes()); err != nil {
+				return nil, err
+			}
+		case "image":
+			if err := writeContent(&out, indent, kind, imageContentTypes(registry.ContentTypes())); err != nil {
+				return nil, err
+			}
+		case "page":
+			if err := writeContent(&out, indent, kind, registry.PageContentTypes()); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("line %d: unknown renderers %q", i+1, kind)
+		}
+		out.WriteString(lines[end])
+		i = end
+	}
+	return out.Bytes(), nil
+}
+
+// writeContent writes the media types of contentTypes with their schema
+func writeContent(out *bytes.Buffer, indent, kind string, contentTypes []string) error {
+	for _, ct := range contentTypes {
+		mediaType, _, err := mime.ParseMediaType(ct)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "%s%s:\n%s  schema:\n", indent, mediaType, indent)
+		switch ref, ok := schemas[kind][mediaType]; {
+		case ok:
+			fmt.Fprintf(out, "%s    $ref: '%s'\n", indent, ref)
+		case isText(mediaType):
+			fmt.Fprintf(out, "%s    type: string\n", indent)
+		default:
+			fmt.Fprintf(out, "%s    type: string\n%s    format: binary\n", indent, indent)
+		}
+	}
+	return nil
+}
+
+// imageContentTypes returns the image content types among contentTypes
+func imageContentTypes(contentTypes []string) []string {
+	var images []string
+	for _, ct := range contentTypes {
+		if strings.HasPrefix(ct, "image/") {
+			images = append(images, ct)
+		}
+	}
+	return images
+}
+
+// isText reports whether mediaType is a textual format
+func isText(mediaType string) bool {
+	return strings.HasPrefix(mediaType, "text/") ||
+		strings.HasSuffix(mediaType, "/json") ||
+		strings.HasSuffix(mediaType, "+json") ||
+		strings.HasSuffix(mediaType, "/xml") ||
+		strings.HasSuffix(mediaType, "+xml")
+}
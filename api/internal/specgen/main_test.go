@@ -0,0 +1,129 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/fgday/quotaday/pkg/quote"
+)
+
+type pngRenderer struct{}
+
+func (pngRenderer) RenderQuote(w io.Writer, q *quote.Quotation, _ quote.RenderOptions) error {
+	return nil
+}
+
+type jsonRenderer struct{ pngRenderer }
+
+func (jsonRenderer) RenderPage(w io.Writer, p *quote.Page, _ quote.RenderOptions) error { return nil }
+
+func TestGenerate(t *testing.T) {
+	spec := `paths:
+  /quote:
+    content:
+      # BEGIN renderers quote
+      text/plain:
+        schema:
+          type: string
+      # END renderers
+  /quotes:
+    content:
+      # BEGIN renderers page
+      # END renderers
+Format:
+  enum:
+    # BEGIN renderers formats
+    # END renderers
+`
+	registry := &quote.Registry{}
+	registry.Register("json", "application/json; charset=UTF-8", jsonRenderer{})
+	registry.Register("svg", "image/svg+xml", pngRenderer{})
+	registry.Register("png", "image/png", pngRenderer{})
+	got, err := generate([]byte(spec), registry)
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+
+	want := `paths:
+  /quote:
+    content:
+      # BEGIN renderers quote
+      application/json:
+        schema:
+          $ref: '#/components/schemas/Quote'
+      image/svg+xml:
+        schema:
+          type: string
+      image/png:
+        schema:
+          type: string
+          format: binary
+      # END renderers
+  /quotes:
+    content:
+      # BEGIN renderers page
+      application/json:
+        schema:
+          $ref: '#/components/schemas/QuoteList'
+      # END renderers
+Format:
+  enum:
+    # BEGIN renderers formats
+    - json
+    - svg
+    - png
+    # END renderers
+`
+	if string(got) != want {
+		t.Errorf("Expected:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	for _, spec := range []string{
+		"# BEGIN renderers quote\n",
+		"# BEGIN renderers unknown\n# END renderers\n",
+	} {
+		if _, err := generate([]byte(spec), quote.NewRegistry()); err == nil {
+			t.Errorf("Expected error generating %q, got nil", spec)
+		}
+	}
+}
+
+// TestSpecUpToDate makes sure all the renderers are advertised in the
+// OpenAPI specification
+func TestSpecUpToDate(t *testing.T) {
+	spec, err := os.ReadFile("../../openapi.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(spec), beginMarker) {
+		t.Fatal("Expected renderers markers in the specification")
+	}
+	got, err := generate(spec, quote.NewRegistry())
+	if err != nil {
+		t.Fatalf("generate failed: %v", err)
+	}
+	if !bytes.Equal(got, spec) {
+		t.Error(`openapi.yaml is out of date, run "go generate ./..."`)
+	}
+}
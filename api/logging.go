@@ -0,0 +1,141 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"cmp"
+	"crypto/rand"
+	"log/slog"
+	"mime"
+	"net/http"
+	"time"
+)
+
+const (
+	// requestIDHeader is the header carrying the ID of a request
+	requestIDHeader = "X-Request-Id"
+	// maxRequestIDLength is the maximum length of the request IDs accepted
+	// from clients
+	maxRequestIDLength = 128
+)
+
+// LogRequests returns next logging each request it serves with logger,
+// once replied. Requests are identified by the ID in their X-Request-Id
+// header, e.g., set by a proxy, or by a random one, which is sent back in
+// the same header and added to all the logs of the request.
+func LogRequests(logger *slog.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		id := requestID(r)
+		w.Header().Set(requestIDHeader, id)
+		r, obs := instrument(r)
+		obs.logger = logger.With("request_id", id)
+		rec := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+		obs.recordRoute(r)
+
+		level := slog.LevelInfo
+		if obs.quiet {
+			level = slog.LevelDebug
+		}
+		attrs := []slog.Attr{
+			slog.String("route", cmp.Or(obs.route, "unmatched")),
+			slog.Int("status", cmp.Or(rec.status, http.StatusOK)),
+			slog.Duration("duration", time.Since(start)),
+		}
+		if mediaType, _, err := mime.ParseMediaType(rec.Header().Get("Content-Type")); err == nil {
+			attrs = append(attrs, slog.String("content_type", mediaType))
+		}
+		attrs = append(attrs, remoteHostAttrs(r)...)
+		obs.logger.LogAttrs(r.Context(), level, "request served", attrs...)
+	})
+}
+
+// requestID returns the ID of r sent by the client if valid, a new random
+// one otherwise
+func requestID(r *http.Request) string {
+	id := r.Header.Get(requestIDHeader)
+	if id == "" || len(id) > maxRequestIDLength {
+		return rand.Text()
+	}
+	for i := 0; i < len(id); i++ {
+		// Only printable ASCII characters, not to mangle logs
+		if id[i] <= ' ' || id[i] > '~' {
+			return rand.Text()
+		}
+	}
+	return id
+}
+
+// requestLogger returns the logger to log with on behalf of r
+func requestLogger(r *http.Request) *slog.Logger {
+	if obs := observe(r); obs != nil && obs.logger != nil {
+		return obs.logger
+	}
+	return slog.Default()
+}
+
+// logQuietly makes r be logged at debug level only
+func logQuietly(r *http.Request) {
+	if obs := observe(r); obs != nil {
+		obs.quiet = true
+	}
+}
+
+// remoteHostAttrs returns the details of the client of r to log, looking
+// through the proxies it may have been forwarded by
+func remoteHostAttrs(r *http.Request) []slog.Attr {
+	// Sample Headers:
+	// Accept:[*/*]
+	// Accept-Encoding:[gzip, br]
+	// Cdn-Loop:[cloudflare; loops=1]
+	// Cf-Connecting-Ip:[1.2.3.4]
+	// Cf-Ipcountry:[IT]
+	// Cf-Ray:[93804dd5edd859f5-MXP]
+	// Cf-Visitor:[{"scheme":"https"}]
+	// User-Agent:[curl/7.88.1]
+	// X-Forwarded-For:[2.3.4.5]
+	// X-Forwarded-Host:[quote.example.com]
+	// X-Forwarded-Port:[80]
+	// X-Forwarded-Proto:[http]
+	// X-Forwarded-Server:[traefik-32bfd46sce-74c3h]
+	// X-Real-Ip:[2.3.4.5]]
+
+	remoteAddr := r.RemoteAddr
+	var country string
+
+	// Proxied through Cloudflare?
+	if remote := r.Header.Get("Cf-Connecting-Ip"); remote != "" {
+		remoteAddr = remote
+		country = r.Header.Get("Cf-Ipcountry")
+	} else if remote := r.Header.Get("X-Real-Ip"); remote != "" {
+		remoteAddr = remote
+	} else if remote := r.Header.Get("X-Forwarded-For"); remote != "" {
+		remoteAddr = remote
+	}
+
+	attrs := []slog.Attr{slog.String("remote_addr", remoteAddr)}
+	if country != "" {
+		attrs = append(attrs, slog.String("country", country))
+	}
+	return append(attrs,
+		slog.String("user_agent", r.Header.Get("User-Agent")),
+		slog.String("method", r.Method),
+		slog.String("proto", r.Proto),
+		slog.String("url", r.URL.String()),
+	)
+}
@@ -0,0 +1,147 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// logRecords decodes the JSON log records written to buf
+func logRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var record map[string]any
+		if err := dec.Decode(&record); err != nil {
+			t.Fatalf("Invalid log record: %s", err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestLogRequests(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	h := LogRequests(logger, Handler(NewServer()))
+
+	req := httptest.NewRequest("POST", "/quote", strings.NewReader(`{"quote": "Logged", "author": "Someone"}`))
+	req.Header.Set("User-Agent", "test/1.0")
+	req.Header.Set("Cf-Connecting-Ip", "1.2.3.4")
+	req.Header.Set("Cf-Ipcountry", "IT")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d", w.Code)
+	}
+	id := w.Header().Get("X-Request-Id")
+	if id == "" {
+		t.Fatal("Expected a generated X-Request-Id header")
+	}
+
+	records := logRecords(t, &buf)
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 log records, got %d: %v", len(records), records)
+	}
+	added, served := records[0], records[1]
+	if added["msg"] != "quote added" || added["quote"] != "Logged" || added["author"] != "Someone" || added["request_id"] != id {
+		t.Errorf("Unexpected log of the added quote: %v", added)
+	}
+	for key, want := range map[string]any{
+		"level":        "INFO",
+		"msg":          "request served",
+		"request_id":   id,
+		"route":        "POST /quote",
+		"status":       float64(http.StatusCreated),
+		"content_type": "application/json",
+		"remote_addr":  "1.2.3.4",
+		"country":      "IT",
+		"user_agent":   "test/1.0",
+		"method":       "POST",
+		"url":          "/quote",
+	} {
+		if served[key] != want {
+			t.Errorf("Expected %s %v in the request log, got %v", key, want, served[key])
+		}
+	}
+	if _, ok := served["duration"]; !ok {
+		t.Errorf("Expected the duration in the request log: %v", served)
+	}
+}
+
+func TestLogRequests_RequestID(t *testing.T) {
+	h := LogRequests(slog.New(slog.DiscardHandler), Handler(NewServer()))
+	for _, tc := range []struct {
+		name string
+		sent string
+		kept bool
+	}{
+		{"valid", "abc-123", true},
+		{"control characters", "abc\x1b[31m", false},
+		{"too long", strings.Repeat("a", maxRequestIDLength+1), false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/healthz", nil)
+			req.Header.Set("X-Request-Id", tc.sent)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+			id := w.Header().Get("X-Request-Id")
+			if kept := id == tc.sent; kept != tc.kept || id == "" {
+				t.Errorf("Unexpected request ID %q for %q", id, tc.sent)
+			}
+		})
+	}
+}
+
+func TestLogRequests_Quiet(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	h := LogRequests(logger, Handler(NewServer()))
+
+	serveRequest(t, h, "GET", "/healthz", "")
+	serveRequest(t, h, "GET", "/nowhere", "")
+	records := logRecords(t, &buf)
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 log records, got %d: %v", len(records), records)
+	}
+	if records[0]["level"] != "DEBUG" || records[0]["route"] != "GET /healthz" {
+		t.Errorf("Expected probes to be logged at debug level: %v", records[0])
+	}
+	if records[1]["level"] != "INFO" || records[1]["route"] != "unmatched" || records[1]["status"] != float64(http.StatusNotFound) {
+		t.Errorf("Unexpected log of an unmatched request: %v", records[1])
+	}
+}
+
+func TestLogRequests_WithMetrics(t *testing.T) {
+	// The route is logged when the Metrics instrument the request too
+	var buf bytes.Buffer
+	server := NewServer()
+	h := LogRequests(slog.New(slog.NewJSONHandler(&buf, nil)), NewMetrics(server.store).Middleware(Handler(server)))
+
+	serveRequest(t, h, "GET", "/quotes/1", "")
+	records := logRecords(t, &buf)
+	if len(records) != 1 || records[0]["route"] != "GET /quotes/{id}" {
+		t.Errorf("Unexpected log records: %v", records)
+	}
+}
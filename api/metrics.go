@@ -0,0 +1,256 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fgday/quotaday/pkg/quote"
+)
+
+// durationBuckets are the upper bounds in seconds of the buckets of the
+// request duration histograms
+var durationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Metrics collects the metrics of the requests served through its
+// Middleware and of the quote Store, exposing them in the Prometheus text
+// format
+type Metrics struct {
+	store quote.Store
+
+	mu sync.Mutex
+	// requests are the durations of the requests by route, status and
+	// media type
+	requests map[requestLabels]*histogram
+	// addFailures are the quotes which could not be added by reason
+	addFailures map[string]uint64
+	// served are the times each quote was served by ID
+	served map[int]uint64
+}
+
+// requestLabels are the labels of the request metrics
+type requestLabels struct {
+	route  string
+	status int
+	mime   string
+}
+
+// histogram counts observations in cumulative buckets
+type histogram struct {
+	// buckets holds the number of observations lower than or equal to
+	// each of durationBuckets
+	buckets []uint64
+	count   uint64
+	sum     float64
+}
+
+// NewMetrics returns the Metrics of the requests to the Server using store
+func NewMetrics(store quote.Store) *Metrics {
+	return &Metrics{
+		store:       store,
+		requests:    map[requestLabels]*histogram{},
+		addFailures: map[string]uint64{},
+		served:      map[int]uint64{},
+	}
+}
+
+// recordServed tells the Metrics that q was served in reply to r
+func recordServed(r *http.Request, q *quote.Quotation) {
+	if obs := observe(r); obs != nil {
+		obs.served = append(obs.served, q.ID)
+	}
+}
+
+// recordAddFailure tells the Metrics that a quote could not be added while
+// serving r because of err
+func recordAddFailure(r *http.Request, err error) {
+	obs := observe(r)
+	if obs == nil {
+		return
+	}
+	switch {
+	case errors.Is(err, quote.ErrFull):
+		obs.addFailure = "full"
+	case errors.Is(err, quote.ErrInvalid):
+		obs.addFailure = "invalid"
+	case errors.Is(err, quote.ErrClosed):
+		obs.addFailure = "closed"
+	default:
+		obs.addFailure = "storage"
+	}
+}
+
+// Middleware returns next instrumented to collect the Metrics of its
+// requests. The route of a request is the pattern of the http.ServeMux
+// which handled it.
+func (m *Metrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		r, obs := instrument(r)
+		rec := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+		obs.recordRoute(r)
+
+		labels := requestLabels{route: obs.route, status: cmp.Or(rec.status, http.StatusOK)}
+		if labels.route == "" {
+			// Do not make a time series of every path requested
+			labels.route = "unmatched"
+		}
+		labels.mime, _, _ = mime.ParseMediaType(rec.Header().Get("Content-Type"))
+		m.observeRequest(labels, time.Since(start), obs)
+	})
+}
+
+// observeRequest records a request
+func (m *Metrics) observeRequest(labels requestLabels, duration time.Duration, obs *observation) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	h, ok := m.requests[labels]
+	if !ok {
+		h = &histogram{buckets: make([]uint64, len(durationBuckets))}
+		m.requests[labels] = h
+	}
+	seconds := duration.Seconds()
+	for i, bound := range durationBuckets {
+		if seconds <= bound {
+			h.buckets[i]++
+		}
+	}
+	h.count++
+	h.sum += seconds
+
+	for _, id := range obs.served {
+		m.served[id]++
+	}
+	if obs.addFailure != "" {
+		m.addFailures[obs.addFailure]++
+	}
+}
+
+// ServeHTTP serves the Metrics in the Prometheus text format. Scrapes are
+// logged quietly, as they are frequent.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logQuietly(r)
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if err := m.write(w); err != nil {
+		requestLogger(r).Warn("error writing metrics", "error", err)
+	}
+}
+
+// write writes the Metrics in the Prometheus text format
+func (m *Metrics) write(w io.Writer) error {
+	var b strings.Builder
+	count := m.store.Count()
+	deleted := m.deletedQuotes()
+
+	m.mu.Lock()
+	// IDs are never reused, so the series of deleted quotes would only
+	// pile up
+	for _, id := range deleted {
+		delete(m.served, id)
+	}
+	requests := slices.SortedFunc(maps.Keys(m.requests), func(a, b requestLabels) int {
+		return cmp.Or(cmp.Compare(a.route, b.route), cmp.Compare(a.status, b.status), cmp.Compare(a.mime, b.mime))
+	})
+
+	writeHeader(&b, "quotaday_http_requests_total", "counter", "Number of HTTP requests by route, status code and media type.")
+	for _, labels := range requests {
+		fmt.Fprintf(&b, "quotaday_http_requests_total%s %d\n", labels.format(""), m.requests[labels].count)
+	}
+
+	writeHeader(&b, "quotaday_http_request_duration_seconds", "histogram", "Duration of the HTTP requests by route, status code and media type.")
+	for _, labels := range requests {
+		h := m.requests[labels]
+		for i, bound := range durationBuckets {
+			fmt.Fprintf(&b, "quotaday_http_request_duration_seconds_bucket%s %d\n", labels.format(formatFloat(bound)), h.buckets[i])
+		}
+		fmt.Fprintf(&b, "quotaday_http_request_duration_seconds_bucket%s %d\n", labels.format("+Inf"), h.count)
+		fmt.Fprintf(&b, "quotaday_http_request_duration_seconds_sum%s %s\n", labels.format(""), formatFloat(h.sum))
+		fmt.Fprintf(&b, "quotaday_http_request_duration_seconds_count%s %d\n", labels.format(""), h.count)
+	}
+
+	writeHeader(&b, "quotaday_quote_add_failures_total", "counter", "Number of quotes which could not be added, by reason.")
+	for _, reason := range slices.Sorted(maps.Keys(m.addFailures)) {
+		fmt.Fprintf(&b, "quotaday_quote_add_failures_total{reason=%s} %d\n", quoteLabel(reason), m.addFailures[reason])
+	}
+
+	writeHeader(&b, "quotaday_quote_served_total", "counter", "Number of times each quote was served, by quote ID.")
+	for _, id := range slices.Sorted(maps.Keys(m.served)) {
+		fmt.Fprintf(&b, "quotaday_quote_served_total{id=\"%d\"} %d\n", id, m.served[id])
+	}
+	m.mu.Unlock()
+
+	writeHeader(&b, "quotaday_quotes", "gauge", "Number of stored quotes.")
+	fmt.Fprintf(&b, "quotaday_quotes %d\n", count)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// deletedQuotes returns the IDs of the served quotes which were deleted
+// from the store
+func (m *Metrics) deletedQuotes() []int {
+	m.mu.Lock()
+	ids := slices.Collect(maps.Keys(m.served))
+	m.mu.Unlock()
+
+	var deleted []int
+	for _, id := range ids {
+		if _, err := m.store.GetQuote(id); errors.Is(err, quote.ErrNotFound) {
+			deleted = append(deleted, id)
+		}
+	}
+	return deleted
+}
+
+// format returns the labels in the Prometheus text format, with the le
+// label of histogram buckets if not empty
+func (l requestLabels) format(le string) string {
+	s := fmt.Sprintf("{route=%s,status=\"%d\",mime=%s", quoteLabel(l.route), l.status, quoteLabel(l.mime))
+	if le != "" {
+		s += fmt.Sprintf(",le=%q", le)
+	}
+	return s + "}"
+}
+
+// writeHeader writes the HELP and TYPE lines of a metric
+func writeHeader(b *strings.Builder, name, typ, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+// quoteLabel returns the label value s quoted and escaped as the Prometheus
+// text format requires
+func quoteLabel(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+// formatFloat formats f as the Prometheus text format requires
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
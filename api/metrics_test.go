@@ -0,0 +1,105 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/fgday/quotaday/pkg/quote"
+)
+
+func TestMetrics(t *testing.T) {
+	qb := quote.New()
+	qb.FillExample()
+	qb.SetMaxQuotes(qb.Count())
+	metrics := NewMetrics(qb)
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", metrics)
+	h := metrics.Middleware(HandlerFromMux(NewServer(WithStore(qb)), mux))
+
+	serveRequest(t, h, "GET", "/quote?id=1", "")
+	serveRequest(t, h, "GET", "/quotes/1", "")
+	serveRequest(t, h, "GET", "/quote?id=2&format=text", "")
+	serveRequest(t, h, "GET", "/quote?id=100", "")
+	serveRequest(t, h, "GET", "/nowhere", "")
+	serveRequest(t, h, "POST", "/quote", `{"quote": "One too many"}`)
+
+	resp := serveRequest(t, h, "GET", "/metrics", "")
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Unexpected content type %q", ct)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	for _, want := range []string{
+		`quotaday_http_requests_total{route="GET /quote",status="200",mime="application/json"} 1`,
+		`quotaday_http_requests_total{route="GET /quote",status="200",mime="text/plain"} 1`,
+		`quotaday_http_requests_total{route="GET /quote",status="404",mime=""} 1`,
+		`quotaday_http_requests_total{route="GET /quotes/{id}",status="200",mime="application/json"} 1`,
+		`quotaday_http_requests_total{route="unmatched",status="404",mime="text/plain"} 1`,
+		`quotaday_http_requests_total{route="POST /quote",status="507",mime=""} 1`,
+		`quotaday_http_request_duration_seconds_bucket{route="GET /quotes/{id}",status="200",mime="application/json",le="+Inf"} 1`,
+		`quotaday_http_request_duration_seconds_count{route="GET /quotes/{id}",status="200",mime="application/json"} 1`,
+		"# TYPE quotaday_http_request_duration_seconds histogram\n",
+		`quotaday_quote_add_failures_total{reason="full"} 1`,
+		`quotaday_quote_served_total{id="1"} 2`,
+		`quotaday_quote_served_total{id="2"} 1`,
+		fmt.Sprintf("quotaday_quotes %d\n", qb.Count()),
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("Expected %q in metrics:\n%s", want, body)
+		}
+	}
+}
+
+func TestMetrics_DeletedQuote(t *testing.T) {
+	qb := quote.New()
+	qb.FillExample()
+	metrics := NewMetrics(qb)
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", metrics)
+	h := metrics.Middleware(HandlerFromMux(NewServer(WithStore(qb)), mux))
+
+	serveRequest(t, h, "GET", "/quote?id=1", "")
+	serveRequest(t, h, "GET", "/quote?id=2", "")
+	if resp := serveRequest(t, h, "DELETE", "/quotes/1", ""); resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("Expected 204, got %d", resp.StatusCode)
+	}
+
+	// The series of deleted quotes are dropped
+	resp := serveRequest(t, h, "GET", "/metrics", "")
+	body, _ := io.ReadAll(resp.Body)
+	if strings.Contains(string(body), `quotaday_quote_served_total{id="1"}`) {
+		t.Errorf("Unexpected series of the deleted quote in metrics:\n%s", body)
+	}
+	if !strings.Contains(string(body), `quotaday_quote_served_total{id="2"} 1`) {
+		t.Errorf("Expected the series of the stored quote in metrics:\n%s", body)
+	}
+}
+
+func TestMetrics_NotInstrumented(t *testing.T) {
+	// Handlers work without the middleware too
+	h := HandlerFromMux(NewServer(), http.NewServeMux())
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/quote?id=1", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected 200, got %d", w.Code)
+	}
+}
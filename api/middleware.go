@@ -0,0 +1,90 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+)
+
+// observationKey is the context key of the observation of a request
+type observationKey struct{}
+
+// observation holds what handlers tell about a request to the middlewares
+// instrumenting it, which share it
+type observation struct {
+	// route is the pattern of the route which served the request, empty
+	// if none matched
+	route string
+	// logger logs on behalf of the request, nil to use the default one
+	logger *slog.Logger
+	// quiet requests are only logged at debug level, e.g., frequent probes
+	quiet      bool
+	served     []int
+	addFailure string
+}
+
+// observe returns the observation of the request r, nil if r is not
+// instrumented
+func observe(r *http.Request) *observation {
+	obs, _ := r.Context().Value(observationKey{}).(*observation)
+	return obs
+}
+
+// instrument returns r along with its observation, which is shared with
+// the middlewares already instrumenting r if any
+func instrument(r *http.Request) (*http.Request, *observation) {
+	if obs := observe(r); obs != nil {
+		return r, obs
+	}
+	obs := &observation{}
+	return r.WithContext(context.WithValue(r.Context(), observationKey{}, obs)), obs
+}
+
+// recordRoute records the route which served r, as r.Pattern is only set
+// on the request passed to the matching handler
+func (obs *observation) recordRoute(r *http.Request) {
+	if r.Pattern != "" {
+		obs.route = r.Pattern
+	}
+}
+
+// statusRecorder is a ResponseWriter recording the status code
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (rec *statusRecorder) WriteHeader(code int) {
+	if rec.status == 0 {
+		rec.status = code
+	}
+	rec.ResponseWriter.WriteHeader(code)
+}
+
+func (rec *statusRecorder) Write(b []byte) (int, error) {
+	if rec.status == 0 {
+		rec.status = http.StatusOK
+	}
+	return rec.ResponseWriter.Write(b)
+}
+
+// Unwrap allows http.ResponseController to reach the original ResponseWriter
+func (rec *statusRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
@@ -0,0 +1,218 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"mime"
+	"strconv"
+	"strings"
+)
+
+// mediaRange is an element of the Accept header, as defined in
+// RFC 7231, section 5.3.2
+type mediaRange struct {
+	typ     string
+	subtype string
+	params  map[string]string
+	// q is the relative weight of the media range, between 0 and 1
+	q float64
+	// index is the position of the media range in the Accept header
+	index int
+}
+
+// precedence returns how specific the media range is: more specific
+// ranges override less specific ones matching the same media type
+func (m mediaRange) precedence() int {
+	switch {
+	case m.typ == "*":
+		return 0
+	case m.subtype == "*":
+		return 1
+	default:
+		return 2 + len(m.params)
+	}
+}
+
+// matches reports whether the media type typ/subtype with the given
+// parameters belongs to the media range
+func (m mediaRange) matches(typ, subtype string, params map[string]string) bool {
+	if m.typ != "*" && m.typ != typ {
+		return false
+	}
+	if m.subtype != "*" && m.subtype != subtype {
+		return false
+	}
+	for k, v := range m.params {
+		if !strings.EqualFold(params[k], v) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseAccept returns the media ranges listed in the given values of the
+// Accept header. Invalid elements are skipped.
+func parseAccept(values []string) []mediaRange {
+	var ranges []mediaRange
+	for _, value := range values {
+		for _, elem := range splitQuoted(value, ',') {
+			if strings.TrimSpace(elem) == "" {
+				continue
+			}
+			if m, ok := parseMediaRange(elem); ok {
+				m.index = len(ranges)
+				ranges = append(ranges, m)
+			}
+		}
+	}
+	return ranges
+}
+
+// parseMediaRange parses a single media range with its parameters and weight
+func parseMediaRange(s string) (mediaRange, bool) {
+	parts := splitQuoted(s, ';')
+	m := mediaRange{q: 1, params: map[string]string{}}
+
+	mt := strings.ToLower(strings.TrimSpace(parts[0]))
+	// Some old clients send a bare "*" meaning any media type
+	if mt == "*" {
+		mt = "*/*"
+	}
+	typ, subtype, ok := strings.Cut(mt, "/")
+	if !ok || !isToken(typ) || !isToken(subtype) || (typ == "*" && subtype != "*") {
+		return m, false
+	}
+	m.typ, m.subtype = typ, subtype
+
+	for _, p := range parts[1:] {
+		key, value, ok := strings.Cut(p, "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+		if !ok || !isToken(key) {
+			return m, false
+		}
+		if strings.HasPrefix(value, `"`) {
+			unquoted, err := strconv.Unquote(value)
+			if err != nil {
+				return m, false
+			}
+			value = unquoted
+		}
+		if key == "q" {
+			q, ok := parseQuality(value)
+			if !ok {
+				return m, false
+			}
+			m.q = q
+			// Anything after the weight is an accept-ext parameter,
+			// which is not part of the media range
+			break
+		}
+		m.params[key] = value
+	}
+	return m, true
+}
+
+// parseQuality parses a qvalue: a number between 0 and 1 with at most
+// three decimal digits
+func parseQuality(s string) (float64, bool) {
+	if s == "" || len(s) > 5 || (s[0] != '0' && s[0] != '1') {
+		return 0, false
+	}
+	if len(s) > 1 && s[1] != '.' {
+		return 0, false
+	}
+	q, err := strconv.ParseFloat(s, 64)
+	if err != nil || q < 0 || q > 1 {
+		return 0, false
+	}
+	return q, true
+}
+
+// negotiate returns the offered content type best matching the Accept
+// header values: the one with the highest weight, then the one matched by
+// the most specific media range, then the one the client listed first.
+// Offers are in server preference order, the first one is also served
+// when the client sent no valid Accept header. It returns false when none
+// of the offers is acceptable.
+func negotiate(accept []string, offers []string) (string, bool) {
+	ranges := parseAccept(accept)
+	if len(ranges) == 0 {
+		ranges = []mediaRange{{typ: "*", subtype: "*", q: 1}}
+	}
+
+	best, bestRange := "", mediaRange{}
+	for _, offer := range offers {
+		mt, params, err := mime.ParseMediaType(offer)
+		if err != nil {
+			continue
+		}
+		typ, subtype, _ := strings.Cut(mt, "/")
+
+		// The most specific matching range sets the offer weight
+		match, found := mediaRange{}, false
+		for _, m := range ranges {
+			if m.matches(typ, subtype, params) && (!found || m.precedence() > match.precedence()) {
+				match, found = m, true
+			}
+		}
+		if !found || match.q == 0 {
+			continue
+		}
+
+		if best == "" ||
+			match.q > bestRange.q ||
+			(match.q == bestRange.q && match.precedence() > bestRange.precedence()) ||
+			(match.q == bestRange.q && match.precedence() == bestRange.precedence() && match.index < bestRange.index) {
+			best, bestRange = offer, match
+		}
+	}
+	return best, best != ""
+}
+
+// splitQuoted splits s at each sep which is not within a quoted string
+func splitQuoted(s string, sep byte) []string {
+	var parts []string
+	quoted, escaped, start := false, false, 0
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case escaped:
+			escaped = false
+		case quoted && c == '\\':
+			escaped = true
+		case c == '"':
+			quoted = !quoted
+		case !quoted && c == sep:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// isToken reports whether s is a non-empty token as defined in RFC 7230
+func isToken(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c > 0x7e || c <= ' ' || strings.ContainsRune(`"(),/:;<=>?@[\]{}`, c) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,87 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	offers := []string{"application/json; charset=UTF-8", "text/html; charset=UTF-8"}
+	tests := []struct {
+		name   string
+		accept []string
+		want   string
+	}{
+		{"missing header", nil, offers[0]},
+		{"any", []string{"*/*"}, offers[0]},
+		{"bare star", []string{"*; q=0.2"}, offers[0]},
+		{"exact", []string{"text/html"}, offers[1]},
+		{"case insensitive", []string{"Text/HTML"}, offers[1]},
+		{"client order", []string{"text/html, application/json"}, offers[1]},
+		{"client order without spaces", []string{"application/json,text/html"}, offers[0]},
+		{"quality", []string{"text/html;q=0.9, application/json;q=0.8"}, offers[1]},
+		{"quality with spaces", []string{"application/json ; q=0.5 , text/html ; q=0.6"}, offers[1]},
+		{"type wildcard", []string{"text/*"}, offers[1]},
+		{"application wildcard", []string{"application/*"}, offers[0]},
+		{"specific beats wildcard", []string{"text/*, application/json"}, offers[0]},
+		{"specific overrides wildcard weight", []string{"*/*;q=0.8, application/json;q=0.1"}, offers[1]},
+		{"browser", []string{"text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"}, offers[1]},
+		{"multiple header values", []string{"image/png", "text/html"}, offers[1]},
+		{"matching parameter", []string{"text/html;charset=utf-8"}, offers[1]},
+		{"accept extension", []string{"text/html;q=1;foo=bar"}, offers[1]},
+		{"quoted comma", []string{`text/plain;foo="a,b", text/html;q=0.5`}, offers[1]},
+		{"invalid elements skipped", []string{"/, text/html;q=2, application/json;q=0.3"}, offers[0]},
+		{"only invalid", []string{"garbage"}, offers[0]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := negotiate(tt.accept, offers)
+			if !ok || got != tt.want {
+				t.Errorf("negotiate(%q) = %q, %t, expected %q", tt.accept, got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestNegotiate_NotAcceptable(t *testing.T) {
+	offers := []string{"application/json; charset=UTF-8", "text/html; charset=UTF-8"}
+	for _, accept := range []string{
+		"image/png",
+		"text/*;q=0, application/json;q=0",
+		"*/*;q=0",
+		"text/html;level=1",
+		"text/html;charset=iso-8859-1",
+	} {
+		if got, ok := negotiate([]string{accept}, offers); ok {
+			t.Errorf("negotiate(%q) = %q, expected no match", accept, got)
+		}
+	}
+}
+
+func TestParseQuality(t *testing.T) {
+	for s, want := range map[string]float64{"0": 0, "1": 1, "0.5": 0.5, "1.000": 1, "0.123": 0.123, "0.": 0} {
+		if got, ok := parseQuality(s); !ok || got != want {
+			t.Errorf("parseQuality(%q) = %v, %t, expected %v", s, got, ok, want)
+		}
+	}
+	for _, s := range []string{"", "2", "1.1", "0.1234", "-0", ".5", "01", "abc"} {
+		if got, ok := parseQuality(s); ok {
+			t.Errorf("parseQuality(%q) = %v, expected invalid", s, got)
+		}
+	}
+}
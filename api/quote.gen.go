@@ -12,35 +12,428 @@ import (
 	"github.com/oapi-codegen/runtime"
 )
 
+// Defines values for Format.
+const (
+	FormatHtml     Format = "html"
+	FormatJson     Format = "json"
+	FormatMarkdown Format = "markdown"
+	FormatPng      Format = "png"
+	FormatSvg      Format = "svg"
+	FormatText     Format = "text"
+)
+
+// Defines values for HealthStatus.
+const (
+	Ok          HealthStatus = "ok"
+	Unavailable HealthStatus = "unavailable"
+)
+
+// Defines values for Mode.
+const (
+	Daily  Mode = "daily"
+	Random Mode = "random"
+)
+
+// Defines values for Theme.
+const (
+	Auto  Theme = "auto"
+	Dark  Theme = "dark"
+	Light Theme = "light"
+)
+
+// Defines values for ExportQuotesParamsFormat.
+const (
+	ExportQuotesParamsFormatCsv      ExportQuotesParamsFormat = "csv"
+	ExportQuotesParamsFormatFortune  ExportQuotesParamsFormat = "fortune"
+	ExportQuotesParamsFormatJson     ExportQuotesParamsFormat = "json"
+	ExportQuotesParamsFormatJsonl    ExportQuotesParamsFormat = "jsonl"
+	ExportQuotesParamsFormatMarkdown ExportQuotesParamsFormat = "markdown"
+	ExportQuotesParamsFormatYaml     ExportQuotesParamsFormat = "yaml"
+)
+
+// Defines values for ImportQuotesParamsFormat.
+const (
+	Csv     ImportQuotesParamsFormat = "csv"
+	Fortune ImportQuotesParamsFormat = "fortune"
+	Json    ImportQuotesParamsFormat = "json"
+	Jsonl   ImportQuotesParamsFormat = "jsonl"
+	Yaml    ImportQuotesParamsFormat = "yaml"
+)
+
+// Format defines model for Format.
+type Format string
+
+// Health defines model for Health.
+type Health struct {
+	// Error Why the server is not ready
+	Error  *string      `json:"error,omitempty"`
+	Status HealthStatus `json:"status"`
+}
+
+// HealthStatus defines model for Health.Status.
+type HealthStatus string
+
+// ImportReport defines model for ImportReport.
+type ImportReport struct {
+	DryRun bool `json:"dry_run"`
+	Errors []struct {
+		Error string `json:"error"`
+		Line  int    `json:"line"`
+	} `json:"errors"`
+
+	// Imported Number of quotations imported, or which would be imported in a dry run
+	Imported int `json:"imported"`
+}
+
+// Mode defines model for Mode.
+type Mode string
+
+// OEmbed defines model for OEmbed.
+type OEmbed struct {
+	AuthorName *string `json:"author_name,omitempty"`
+
+	// CacheAge Seconds the response can be cached for
+	CacheAge     *int    `json:"cache_age,omitempty"`
+	Height       int     `json:"height"`
+	Html         string  `json:"html"`
+	ProviderName *string `json:"provider_name,omitempty"`
+	ProviderUrl  *string `json:"provider_url,omitempty"`
+	Title        *string `json:"title,omitempty"`
+	Type         string  `json:"type"`
+	Version      string  `json:"version"`
+	Width        int     `json:"width"`
+}
+
 // Quote defines model for Quote.
 type Quote struct {
 	Author *string `json:"author,omitempty"`
-	Quote  string  `json:"quote"`
+
+	// Id Unique and stable identifier of the quote
+	Id    *int      `json:"id,omitempty"`
+	Quote string    `json:"quote"`
+	Tags  *[]string `json:"tags,omitempty"`
+}
+
+// QuoteList defines model for QuoteList.
+type QuoteList struct {
+	Limit  int     `json:"limit"`
+	Offset int     `json:"offset"`
+	Quotes []Quote `json:"quotes"`
+
+	// Total Total number of available quotations
+	Total int `json:"total"`
+}
+
+// QuotePatch defines model for QuotePatch.
+type QuotePatch struct {
+	Author *string   `json:"author,omitempty"`
+	Quote  *string   `json:"quote,omitempty"`
+	Tags   *[]string `json:"tags,omitempty"`
+}
+
+// SearchResult defines model for SearchResult.
+type SearchResult struct {
+	Author *string `json:"author,omitempty"`
+
+	// AuthorSnippet HTML escaped author with the matching words wrapped in <mark> tags
+	AuthorSnippet string `json:"author_snippet"`
+
+	// Id Unique and stable identifier of the quote
+	Id    *int   `json:"id,omitempty"`
+	Quote string `json:"quote"`
+
+	// Score Relevance of the quotation for the query
+	Score float32 `json:"score"`
+
+	// Snippet HTML escaped quotation, shortened around the first match, with the matching words wrapped in <mark> tags
+	Snippet string    `json:"snippet"`
+	Tags    *[]string `json:"tags,omitempty"`
 }
 
+// TagCount defines model for TagCount.
+type TagCount struct {
+	Count int    `json:"count"`
+	Tag   string `json:"tag"`
+}
+
+// Theme defines model for Theme.
+type Theme string
+
+// Author defines model for Author.
+type Author = string
+
+// Days defines model for Days.
+type Days = int
+
+// Id defines model for Id.
+type Id = int
+
+// Tag defines model for Tag.
+type Tag = string
+
+// Width defines model for Width.
+type Width = int
+
 // Error defines model for Error.
 type Error struct {
 	Code    string `json:"code"`
 	Message string `json:"message"`
 }
 
+// GetCalendarParams defines parameters for GetCalendar.
+type GetCalendarParams struct {
+	// Days Number of upcoming days, starting from today
+	Days *int `form:"days,omitempty" json:"days,omitempty"`
+}
+
+// GetEmbedParams defines parameters for GetEmbed.
+type GetEmbedParams struct {
+	// Id ID of the quotation to return
+	Id *Id `form:"id,omitempty" json:"id,omitempty"`
+
+	// Mode How the quotation is picked when no id is given: "random" picks a different one on each request, "daily" returns the quote of the day
+	Mode *Mode `form:"mode,omitempty" json:"mode,omitempty"`
+
+	// Tag Picks the quotation only among the ones with this tag
+	Tag *Tag `form:"tag,omitempty" json:"tag,omitempty"`
+
+	// Author Picks the quotation only among the ones by this author
+	Author *Author `form:"author,omitempty" json:"author,omitempty"`
+
+	// Theme Color theme of HTML pages: "auto" follows the settings of the browser
+	Theme *Theme `form:"theme,omitempty" json:"theme,omitempty"`
+}
+
+// GetAtomFeedParams defines parameters for GetAtomFeed.
+type GetAtomFeedParams struct {
+	// Days Number of days to return the quote of the day of
+	Days *Days `form:"days,omitempty" json:"days,omitempty"`
+}
+
+// GetRssFeedParams defines parameters for GetRssFeed.
+type GetRssFeedParams struct {
+	// Days Number of days to return the quote of the day of
+	Days *Days `form:"days,omitempty" json:"days,omitempty"`
+}
+
+// GetOEmbedParams defines parameters for GetOEmbed.
+type GetOEmbedParams struct {
+	// Url URL of a quotation, e.g., of /quotes/{id}, /quote or /quote/today
+	Url string `form:"url" json:"url"`
+
+	// Maxwidth Maximum width of the iframe in pixels
+	Maxwidth *int `form:"maxwidth,omitempty" json:"maxwidth,omitempty"`
+
+	// Maxheight Maximum height of the iframe in pixels
+	Maxheight *int `form:"maxheight,omitempty" json:"maxheight,omitempty"`
+
+	// Format Format of the response, only json is implemented
+	Format *string `form:"format,omitempty" json:"format,omitempty"`
+}
+
 // GetQuoteParams defines parameters for GetQuote.
 type GetQuoteParams struct {
-	// Id Identifies the i-th quotation to return
-	Id *int `form:"id,omitempty" json:"id,omitempty"`
+	// Id ID of the quotation to return
+	Id *Id `form:"id,omitempty" json:"id,omitempty"`
+
+	// Mode How the quotation is picked when no id is given: "random" picks a different one on each request, "daily" returns the quote of the day
+	Mode *Mode `form:"mode,omitempty" json:"mode,omitempty"`
+
+	// Tag Picks the quotation only among the ones with this tag
+	Tag *Tag `form:"tag,omitempty" json:"tag,omitempty"`
+
+	// Author Picks the quotation only among the ones by this author
+	Author *Author `form:"author,omitempty" json:"author,omitempty"`
+
+	// Format Format of the quotations, overriding the Accept header
+	Format *Format `form:"format,omitempty" json:"format,omitempty"`
+
+	// Width Number of characters plain text is wrapped to, not wrapped if 0
+	Width *Width `form:"width,omitempty" json:"width,omitempty"`
+
+	// Theme Color theme of HTML pages: "auto" follows the settings of the browser
+	Theme *Theme `form:"theme,omitempty" json:"theme,omitempty"`
+}
+
+// GetQuoteImageParams defines parameters for GetQuoteImage.
+type GetQuoteImageParams struct {
+	// Id ID of the quotation to return
+	Id *Id `form:"id,omitempty" json:"id,omitempty"`
+
+	// Mode How the quotation is picked when no id is given: "random" picks a different one on each request, "daily" returns the quote of the day
+	Mode *Mode `form:"mode,omitempty" json:"mode,omitempty"`
+
+	// Tag Picks the quotation only among the ones with this tag
+	Tag *Tag `form:"tag,omitempty" json:"tag,omitempty"`
+
+	// Author Picks the quotation only among the ones by this author
+	Author *Author `form:"author,omitempty" json:"author,omitempty"`
+
+	// Format Format of the quotations, overriding the Accept header
+	Format *Format `form:"format,omitempty" json:"format,omitempty"`
+}
+
+// GetDailyQuoteParams defines parameters for GetDailyQuote.
+type GetDailyQuoteParams struct {
+	// Format Format of the quotations, overriding the Accept header
+	Format *Format `form:"format,omitempty" json:"format,omitempty"`
+
+	// Width Number of characters plain text is wrapped to, not wrapped if 0
+	Width *Width `form:"width,omitempty" json:"width,omitempty"`
+
+	// Theme Color theme of HTML pages: "auto" follows the settings of the browser
+	Theme *Theme `form:"theme,omitempty" json:"theme,omitempty"`
+}
+
+// ListQuotesParams defines parameters for ListQuotes.
+type ListQuotesParams struct {
+	// Offset Position of the first quotation to return
+	Offset *int `form:"offset,omitempty" json:"offset,omitempty"`
+
+	// Limit Maximum number of quotations to return
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+
+	// Format Format of the quotations, overriding the Accept header
+	Format *Format `form:"format,omitempty" json:"format,omitempty"`
+
+	// Width Number of characters plain text is wrapped to, not wrapped if 0
+	Width *Width `form:"width,omitempty" json:"width,omitempty"`
+
+	// Theme Color theme of HTML pages: "auto" follows the settings of the browser
+	Theme *Theme `form:"theme,omitempty" json:"theme,omitempty"`
+}
+
+// SearchQuotesParams defines parameters for SearchQuotes.
+type SearchQuotesParams struct {
+	// Q Words to search for
+	Q string `form:"q" json:"q"`
+
+	// Limit Maximum number of results to return
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// GetQuoteByIdParams defines parameters for GetQuoteById.
+type GetQuoteByIdParams struct {
+	// Format Format of the quotations, overriding the Accept header
+	Format *Format `form:"format,omitempty" json:"format,omitempty"`
+
+	// Width Number of characters plain text is wrapped to, not wrapped if 0
+	Width *Width `form:"width,omitempty" json:"width,omitempty"`
+
+	// Theme Color theme of HTML pages: "auto" follows the settings of the browser
+	Theme *Theme `form:"theme,omitempty" json:"theme,omitempty"`
+}
+
+// ExportQuotesParams defines parameters for ExportQuotes.
+type ExportQuotesParams struct {
+	// Format Format of the exported quotations
+	Format *ExportQuotesParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+}
+
+// ExportQuotesParamsFormat defines parameters for ExportQuotes.
+type ExportQuotesParamsFormat string
+
+// ImportQuotesJSONBody defines parameters for ImportQuotes.
+type ImportQuotesJSONBody = []Quote
+
+// ImportQuotesTextBody defines parameters for ImportQuotes.
+type ImportQuotesTextBody = string
+
+// ImportQuotesParams defines parameters for ImportQuotes.
+type ImportQuotesParams struct {
+	// Format Format of the request body, guessed from its Content-Type if not set
+	Format *ImportQuotesParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+
+	// DryRun Only check the quotations, including whether there is room for them, without adding them
+	DryRun *bool `form:"dry_run,omitempty" json:"dry_run,omitempty"`
+
+	// KeepIds Keep the IDs of the quotations, e.g., to restore a backup, instead of assigning new ones. Quotations with an ID in use are invalid. Only the json, jsonl and yaml formats have IDs.
+	KeepIds *bool `form:"keep_ids,omitempty" json:"keep_ids,omitempty"`
 }
 
+// ImportQuotesParamsFormat defines parameters for ImportQuotes.
+type ImportQuotesParamsFormat string
+
 // PostQuoteJSONRequestBody defines body for PostQuote for application/json ContentType.
 type PostQuoteJSONRequestBody = Quote
 
+// UpdateQuoteJSONRequestBody defines body for UpdateQuote for application/json ContentType.
+type UpdateQuoteJSONRequestBody = QuotePatch
+
+// ReplaceQuoteJSONRequestBody defines body for ReplaceQuote for application/json ContentType.
+type ReplaceQuoteJSONRequestBody = Quote
+
+// ImportQuotesJSONRequestBody defines body for ImportQuotes for application/json ContentType.
+type ImportQuotesJSONRequestBody = ImportQuotesJSONBody
+
+// ImportQuotesTextRequestBody defines body for ImportQuotes for text/plain ContentType.
+type ImportQuotesTextRequestBody = ImportQuotesTextBody
+
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
 
+	// (GET /calendar.ics)
+	GetCalendar(w http.ResponseWriter, r *http.Request, params GetCalendarParams)
+
+	// (GET /embed)
+	GetEmbed(w http.ResponseWriter, r *http.Request, params GetEmbedParams)
+
+	// (GET /embed.js)
+	GetEmbedScript(w http.ResponseWriter, r *http.Request)
+
+	// (GET /feed.atom)
+	GetAtomFeed(w http.ResponseWriter, r *http.Request, params GetAtomFeedParams)
+
+	// (GET /feed.rss)
+	GetRssFeed(w http.ResponseWriter, r *http.Request, params GetRssFeedParams)
+
+	// (GET /healthz)
+	GetHealth(w http.ResponseWriter, r *http.Request)
+
+	// (GET /oembed)
+	GetOEmbed(w http.ResponseWriter, r *http.Request, params GetOEmbedParams)
+
 	// (GET /quote)
 	GetQuote(w http.ResponseWriter, r *http.Request, params GetQuoteParams)
 
 	// (POST /quote)
 	PostQuote(w http.ResponseWriter, r *http.Request)
+
+	// (GET /quote/image)
+	GetQuoteImage(w http.ResponseWriter, r *http.Request, params GetQuoteImageParams)
+
+	// (GET /quote/today)
+	GetDailyQuote(w http.ResponseWriter, r *http.Request, params GetDailyQuoteParams)
+
+	// (GET /quotes)
+	ListQuotes(w http.ResponseWriter, r *http.Request, params ListQuotesParams)
+
+	// (GET /quotes/search)
+	SearchQuotes(w http.ResponseWriter, r *http.Request, params SearchQuotesParams)
+
+	// (DELETE /quotes/{id})
+	DeleteQuote(w http.ResponseWriter, r *http.Request, id int)
+
+	// (GET /quotes/{id})
+	GetQuoteById(w http.ResponseWriter, r *http.Request, id int, params GetQuoteByIdParams)
+
+	// (PATCH /quotes/{id})
+	UpdateQuote(w http.ResponseWriter, r *http.Request, id int)
+
+	// (PUT /quotes/{id})
+	ReplaceQuote(w http.ResponseWriter, r *http.Request, id int)
+
+	// (GET /quotes:export)
+	ExportQuotes(w http.ResponseWriter, r *http.Request, params ExportQuotesParams)
+
+	// (POST /quotes:import)
+	ImportQuotes(w http.ResponseWriter, r *http.Request, params ImportQuotesParams)
+
+	// (GET /readyz)
+	GetReadiness(w http.ResponseWriter, r *http.Request)
+
+	// (GET /tags)
+	ListTags(w http.ResponseWriter, r *http.Request)
 }
 
 // ServerInterfaceWrapper converts contexts to parameters.
@@ -52,13 +445,40 @@ type ServerInterfaceWrapper struct {
 
 type MiddlewareFunc func(http.Handler) http.Handler
 
-// GetQuote operation middleware
-func (siw *ServerInterfaceWrapper) GetQuote(w http.ResponseWriter, r *http.Request) {
+// GetCalendar operation middleware
+func (siw *ServerInterfaceWrapper) GetCalendar(w http.ResponseWriter, r *http.Request) {
 
 	var err error
 
 	// Parameter object where we will unmarshal all parameters from the context
-	var params GetQuoteParams
+	var params GetCalendarParams
+
+	// ------------- Optional query parameter "days" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "days", r.URL.Query(), &params.Days)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "days", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetCalendar(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetEmbed operation middleware
+func (siw *ServerInterfaceWrapper) GetEmbed(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetEmbedParams
 
 	// ------------- Optional query parameter "id" -------------
 
@@ -68,8 +488,40 @@ func (siw *ServerInterfaceWrapper) GetQuote(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	// ------------- Optional query parameter "mode" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "mode", r.URL.Query(), &params.Mode)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mode", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "tag" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "tag", r.URL.Query(), &params.Tag)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "tag", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "author" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "author", r.URL.Query(), &params.Author)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "author", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "theme" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "theme", r.URL.Query(), &params.Theme)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "theme", Err: err})
+		return
+	}
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetQuote(w, r, params)
+		siw.Handler.GetEmbed(w, r, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -79,11 +531,654 @@ func (siw *ServerInterfaceWrapper) GetQuote(w http.ResponseWriter, r *http.Reque
 	handler.ServeHTTP(w, r)
 }
 
-// PostQuote operation middleware
-func (siw *ServerInterfaceWrapper) PostQuote(w http.ResponseWriter, r *http.Request) {
+// GetEmbedScript operation middleware
+func (siw *ServerInterfaceWrapper) GetEmbedScript(w http.ResponseWriter, r *http.Request) {
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.PostQuote(w, r)
+		siw.Handler.GetEmbedScript(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetAtomFeed operation middleware
+func (siw *ServerInterfaceWrapper) GetAtomFeed(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetAtomFeedParams
+
+	// ------------- Optional query parameter "days" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "days", r.URL.Query(), &params.Days)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "days", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetAtomFeed(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetRssFeed operation middleware
+func (siw *ServerInterfaceWrapper) GetRssFeed(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetRssFeedParams
+
+	// ------------- Optional query parameter "days" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "days", r.URL.Query(), &params.Days)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "days", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetRssFeed(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetHealth operation middleware
+func (siw *ServerInterfaceWrapper) GetHealth(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetHealth(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetOEmbed operation middleware
+func (siw *ServerInterfaceWrapper) GetOEmbed(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetOEmbedParams
+
+	// ------------- Required query parameter "url" -------------
+
+	if paramValue := r.URL.Query().Get("url"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "url"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "url", r.URL.Query(), &params.Url)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "url", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "maxwidth" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "maxwidth", r.URL.Query(), &params.Maxwidth)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "maxwidth", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "maxheight" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "maxheight", r.URL.Query(), &params.Maxheight)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "maxheight", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", r.URL.Query(), &params.Format)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "format", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetOEmbed(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetQuote operation middleware
+func (siw *ServerInterfaceWrapper) GetQuote(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetQuoteParams
+
+	// ------------- Optional query parameter "id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "id", r.URL.Query(), &params.Id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "mode" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "mode", r.URL.Query(), &params.Mode)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mode", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "tag" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "tag", r.URL.Query(), &params.Tag)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "tag", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "author" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "author", r.URL.Query(), &params.Author)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "author", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", r.URL.Query(), &params.Format)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "format", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "width" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "width", r.URL.Query(), &params.Width)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "width", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "theme" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "theme", r.URL.Query(), &params.Theme)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "theme", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetQuote(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PostQuote operation middleware
+func (siw *ServerInterfaceWrapper) PostQuote(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostQuote(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetQuoteImage operation middleware
+func (siw *ServerInterfaceWrapper) GetQuoteImage(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetQuoteImageParams
+
+	// ------------- Optional query parameter "id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "id", r.URL.Query(), &params.Id)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "mode" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "mode", r.URL.Query(), &params.Mode)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mode", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "tag" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "tag", r.URL.Query(), &params.Tag)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "tag", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "author" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "author", r.URL.Query(), &params.Author)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "author", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", r.URL.Query(), &params.Format)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "format", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetQuoteImage(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetDailyQuote operation middleware
+func (siw *ServerInterfaceWrapper) GetDailyQuote(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetDailyQuoteParams
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", r.URL.Query(), &params.Format)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "format", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "width" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "width", r.URL.Query(), &params.Width)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "width", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "theme" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "theme", r.URL.Query(), &params.Theme)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "theme", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetDailyQuote(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ListQuotes operation middleware
+func (siw *ServerInterfaceWrapper) ListQuotes(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListQuotesParams
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", r.URL.Query(), &params.Offset)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "offset", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", r.URL.Query(), &params.Format)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "format", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "width" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "width", r.URL.Query(), &params.Width)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "width", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "theme" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "theme", r.URL.Query(), &params.Theme)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "theme", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListQuotes(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// SearchQuotes operation middleware
+func (siw *ServerInterfaceWrapper) SearchQuotes(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params SearchQuotesParams
+
+	// ------------- Required query parameter "q" -------------
+
+	if paramValue := r.URL.Query().Get("q"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "q"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "q", r.URL.Query(), &params.Q)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "q", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.SearchQuotes(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteQuote operation middleware
+func (siw *ServerInterfaceWrapper) DeleteQuote(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteQuote(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetQuoteById operation middleware
+func (siw *ServerInterfaceWrapper) GetQuoteById(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetQuoteByIdParams
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", r.URL.Query(), &params.Format)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "format", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "width" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "width", r.URL.Query(), &params.Width)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "width", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "theme" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "theme", r.URL.Query(), &params.Theme)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "theme", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetQuoteById(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// UpdateQuote operation middleware
+func (siw *ServerInterfaceWrapper) UpdateQuote(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UpdateQuote(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ReplaceQuote operation middleware
+func (siw *ServerInterfaceWrapper) ReplaceQuote(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", r.PathValue("id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ReplaceQuote(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ExportQuotes operation middleware
+func (siw *ServerInterfaceWrapper) ExportQuotes(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ExportQuotesParams
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", r.URL.Query(), &params.Format)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "format", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ExportQuotes(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ImportQuotes operation middleware
+func (siw *ServerInterfaceWrapper) ImportQuotes(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ImportQuotesParams
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", r.URL.Query(), &params.Format)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "format", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "dry_run" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "dry_run", r.URL.Query(), &params.DryRun)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "dry_run", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "keep_ids" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "keep_ids", r.URL.Query(), &params.KeepIds)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "keep_ids", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ImportQuotes(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetReadiness operation middleware
+func (siw *ServerInterfaceWrapper) GetReadiness(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetReadiness(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ListTags operation middleware
+func (siw *ServerInterfaceWrapper) ListTags(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListTags(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -213,8 +1308,27 @@ func HandlerWithOptions(si ServerInterface, options StdHTTPServerOptions) http.H
 		ErrorHandlerFunc:   options.ErrorHandlerFunc,
 	}
 
+	m.HandleFunc("GET "+options.BaseURL+"/calendar.ics", wrapper.GetCalendar)
+	m.HandleFunc("GET "+options.BaseURL+"/embed", wrapper.GetEmbed)
+	m.HandleFunc("GET "+options.BaseURL+"/embed.js", wrapper.GetEmbedScript)
+	m.HandleFunc("GET "+options.BaseURL+"/feed.atom", wrapper.GetAtomFeed)
+	m.HandleFunc("GET "+options.BaseURL+"/feed.rss", wrapper.GetRssFeed)
+	m.HandleFunc("GET "+options.BaseURL+"/healthz", wrapper.GetHealth)
+	m.HandleFunc("GET "+options.BaseURL+"/oembed", wrapper.GetOEmbed)
 	m.HandleFunc("GET "+options.BaseURL+"/quote", wrapper.GetQuote)
 	m.HandleFunc("POST "+options.BaseURL+"/quote", wrapper.PostQuote)
+	m.HandleFunc("GET "+options.BaseURL+"/quote/image", wrapper.GetQuoteImage)
+	m.HandleFunc("GET "+options.BaseURL+"/quote/today", wrapper.GetDailyQuote)
+	m.HandleFunc("GET "+options.BaseURL+"/quotes", wrapper.ListQuotes)
+	m.HandleFunc("GET "+options.BaseURL+"/quotes/search", wrapper.SearchQuotes)
+	m.HandleFunc("DELETE "+options.BaseURL+"/quotes/{id}", wrapper.DeleteQuote)
+	m.HandleFunc("GET "+options.BaseURL+"/quotes/{id}", wrapper.GetQuoteById)
+	m.HandleFunc("PATCH "+options.BaseURL+"/quotes/{id}", wrapper.UpdateQuote)
+	m.HandleFunc("PUT "+options.BaseURL+"/quotes/{id}", wrapper.ReplaceQuote)
+	m.HandleFunc("GET "+options.BaseURL+"/quotes:export", wrapper.ExportQuotes)
+	m.HandleFunc("POST "+options.BaseURL+"/quotes:import", wrapper.ImportQuotes)
+	m.HandleFunc("GET "+options.BaseURL+"/readyz", wrapper.GetReadiness)
+	m.HandleFunc("GET "+options.BaseURL+"/tags", wrapper.ListTags)
 
 	return m
 }
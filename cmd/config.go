@@ -0,0 +1,401 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/netip"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/urfave/cli/v2"
+	"github.com/urfave/cli/v2/altsrc"
+	"gopkg.in/yaml.v3"
+
+	"github.com/fgday/quotaday/pkg/quote"
+)
+
+// configPrecedence documents how the configuration layers override each other
+const configPrecedence = `Each setting can be given, from the lowest to the highest precedence, in:
+  - the YAML or TOML file of --config, with the flag names as keys, e.g., "data-file: quotes.json"
+  - the QUOTADAY_* environment variable named after the flag, e.g., QUOTADAY_DATA_FILE
+  - the command line flag, e.g., --data-file
+Run "quotaday config print" to see the effective configuration.`
+
+// envVars returns the environment variable setting the flag name, e.g.,
+// QUOTADAY_DATA_FILE for --data-file
+func envVars(name string) []string {
+	return []string{"QUOTADAY_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))}
+}
+
+// configFlag sets the configuration file
+var configFlag = &cli.StringFlag{
+	Name:    "config",
+	Aliases: []string{"c"},
+	EnvVars: envVars("config"),
+	Usage:   "YAML (.yaml, .yml) or TOML (.toml) configuration file",
+}
+
+// serverFlags returns the flags configuring Quotaday, which can also be set
+// in the configuration file and through environment variables
+func serverFlags() []cli.Flag {
+	return []cli.Flag{
+		altsrc.NewUintFlag(&cli.UintFlag{
+			Name:    "port",
+			Aliases: []string{"p"},
+			EnvVars: envVars("port"),
+			Usage:   "port to listen to",
+			Value:   80,
+		}),
+		altsrc.NewDurationFlag(&cli.DurationFlag{
+			Name:    "shutdown-timeout",
+			EnvVars: envVars("shutdown-timeout"),
+			Usage:   "how long to wait for the requests in progress when stopping on SIGTERM or SIGINT",
+			Value:   20 * time.Second,
+		}),
+		altsrc.NewBoolFlag(&cli.BoolFlag{
+			Name:    "metrics",
+			EnvVars: envVars("metrics"),
+			Usage:   "expose Prometheus metrics at /metrics, --metrics=false to disable them",
+			Value:   true,
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:    "log-format",
+			EnvVars: envVars("log-format"),
+			Usage:   "format of the logs written to stderr: \"text\" (key=value pairs) or \"json\"",
+			Value:   "text",
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:    "log-level",
+			EnvVars: envVars("log-level"),
+			Usage:   "minimum level of the logs: \"debug\", \"info\", \"warn\" or \"error\"",
+			Value:   "info",
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:    "tls-cert",
+			EnvVars: envVars("tls-cert"),
+			Usage:   "PEM file with the certificate chain to serve HTTPS with, along with --tls-key",
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:    "tls-key",
+			EnvVars: envVars("tls-key"),
+			Usage:   "PEM file with the private key of --tls-cert",
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:    "tls-client-ca",
+			EnvVars: envVars("tls-client-ca"),
+			Usage:   "PEM file with the certificate authorities clients must present a certificate signed by (mutual TLS)",
+		}),
+		altsrc.NewDurationFlag(&cli.DurationFlag{
+			Name:    "tls-reload-interval",
+			EnvVars: envVars("tls-reload-interval"),
+			Usage:   "how often the TLS files are checked for changes and reloaded, 0 to never reload them",
+			Value:   time.Minute,
+		}),
+		altsrc.NewUintFlag(&cli.UintFlag{
+			Name:    "tls-redirect-port",
+			EnvVars: envVars("tls-redirect-port"),
+			Usage:   "port redirecting plain HTTP requests to HTTPS, 0 not to listen to plain HTTP",
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:    "storage",
+			EnvVars: envVars("storage"),
+			Usage:   "storage backend for quotes: \"memory\" or \"file\" (default: \"file\" if --data-file is set, \"memory\" otherwise)",
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:    "data-file",
+			EnvVars: envVars("data-file"),
+			Usage:   "file where quotes are persisted by the \"file\" storage backend",
+		}),
+		altsrc.NewIntFlag(&cli.IntFlag{
+			Name:    "max-quotes",
+			EnvVars: envVars("max-quotes"),
+			Usage:   "maximum number of quotes which can be stored",
+			Value:   20,
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:    "timezone",
+			EnvVars: envVars("timezone"),
+			Usage:   "IANA timezone where the quote of the day rolls over",
+			Value:   "Local",
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:    "base-url",
+			EnvVars: envVars("base-url"),
+			Usage:   "URL the service is reachable at, used in links, e.g., in feeds (default: guessed from each request)",
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:    "trusted-proxies",
+			EnvVars: envVars("trusted-proxies"),
+			Usage:   "comma-separated IP addresses or CIDR networks of the proxies whose X-Forwarded-Host and X-Forwarded-Proto headers are trusted to guess the base URL",
+		}),
+		altsrc.NewIntFlag(&cli.IntFlag{
+			Name:    "card-width",
+			EnvVars: envVars("card-width"),
+			Usage:   "width in pixels of the quote image cards",
+			Value:   1200,
+		}),
+		altsrc.NewIntFlag(&cli.IntFlag{
+			Name:    "card-height",
+			EnvVars: envVars("card-height"),
+			Usage:   "height in pixels of the quote image cards",
+			Value:   630,
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:    "card-background",
+			EnvVars: envVars("card-background"),
+			Usage:   "background color of the quote image cards",
+			Value:   "#1e293b",
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:    "card-foreground",
+			EnvVars: envVars("card-foreground"),
+			Usage:   "color of the quote on the image cards",
+			Value:   "#f8fafc",
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:    "card-accent",
+			EnvVars: envVars("card-accent"),
+			Usage:   "color of the author on the image cards",
+			Value:   "#fbbf24",
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:    "card-font",
+			EnvVars: envVars("card-font"),
+			Usage:   "TrueType or OpenType font file for the quote image cards (default: the embedded Go font)",
+		}),
+		altsrc.NewStringFlag(&cli.StringFlag{
+			Name:    "template-dir",
+			EnvVars: envVars("template-dir"),
+			Usage:   "directory with the HTML templates replacing the built-in ones (layout.html, quote.html, page.html, embed.html)",
+		}),
+		altsrc.NewDurationFlag(&cli.DurationFlag{
+			Name:    "template-reload-interval",
+			EnvVars: envVars("template-reload-interval"),
+			Usage:   "how often the templates in --template-dir are checked for changes and reloaded, 0 to never reload them",
+			Value:   2 * time.Second,
+		}),
+	}
+}
+
+// loadConfig returns the function applying the configuration file to flags,
+// unless they are set on the command line or in the environment,
+// validating the resulting configuration and setting up the logs
+func loadConfig(flags []cli.Flag) cli.BeforeFunc {
+	return func(cCtx *cli.Context) error {
+		if path := cCtx.String("config"); path != "" {
+			src, err := readConfigFile(path, flags)
+			if err != nil {
+				return err
+			}
+			if err := altsrc.ApplyInputSourceValues(cCtx, src, flags); err != nil {
+				return fmt.Errorf("invalid config file %s: %w", path, err)
+			}
+		}
+		if err := validateConfig(cCtx); err != nil {
+			return err
+		}
+		logger, err := newLogger(os.Stderr, cCtx.String("log-format"), cCtx.String("log-level"))
+		if err != nil {
+			return err
+		}
+		slog.SetDefault(logger)
+		return nil
+	}
+}
+
+// readConfigFile reads the values of flags from a YAML or TOML file,
+// depending on its extension
+func readConfigFile(path string, flags []cli.Flag) (altsrc.InputSourceContext, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read the config file: %w", err)
+	}
+	values := map[string]any{}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &values)
+	case ".toml":
+		err = toml.Unmarshal(data, &values)
+	default:
+		return nil, fmt.Errorf("unknown config file format %q, expected .yaml, .yml or .toml", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	known := map[string]bool{}
+	for _, f := range flags {
+		known[f.Names()[0]] = true
+	}
+	valueMap := map[any]any{}
+	for key, value := range values {
+		if !known[key] {
+			return nil, fmt.Errorf("invalid config file %s: unknown setting %q", path, key)
+		}
+		// TOML integers are decoded as int64, while flags read int
+		if n, ok := value.(int64); ok {
+			value = int(n)
+		}
+		valueMap[key] = value
+	}
+	return altsrc.NewMapInputSource(path, valueMap), nil
+}
+
+// validateConfig checks the whole configuration at startup, so that all
+// the mistakes are reported at once, before serving any request
+func validateConfig(cCtx *cli.Context) error {
+	var errs []error
+	for _, name := range []string{"port", "tls-redirect-port"} {
+		if port := cCtx.Uint(name); port > 65535 {
+			errs = append(errs, fmt.Errorf("invalid %s %d", name, port))
+		}
+	}
+	if (cCtx.String("tls-cert") == "") != (cCtx.String("tls-key") == "") {
+		errs = append(errs, fmt.Errorf("tls-cert and tls-key must be set together"))
+	}
+	if cCtx.String("tls-cert") == "" {
+		if cCtx.String("tls-client-ca") != "" {
+			errs = append(errs, fmt.Errorf("tls-client-ca requires tls-cert and tls-key"))
+		}
+		if cCtx.Uint("tls-redirect-port") != 0 {
+			errs = append(errs, fmt.Errorf("tls-redirect-port requires tls-cert and tls-key"))
+		}
+	}
+	if port := cCtx.Uint("tls-redirect-port"); port != 0 && port == cCtx.Uint("port") {
+		errs = append(errs, fmt.Errorf("tls-redirect-port must differ from port"))
+	}
+	switch storage := cCtx.String("storage"); storage {
+	case "", "memory":
+	case "file":
+		if cCtx.String("data-file") == "" {
+			errs = append(errs, fmt.Errorf("the \"file\" storage backend requires data-file"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("unknown storage backend %q", storage))
+	}
+	if n := cCtx.Int("max-quotes"); n < 1 {
+		errs = append(errs, fmt.Errorf("invalid max-quotes %d", n))
+	}
+	if _, err := time.LoadLocation(cCtx.String("timezone")); err != nil {
+		errs = append(errs, fmt.Errorf("invalid timezone: %w", err))
+	}
+	if base := cCtx.String("base-url"); base != "" {
+		u, err := url.Parse(base)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("invalid base URL %q", base))
+		}
+	}
+	if _, err := parseTrustedProxies(cCtx.String("trusted-proxies")); err != nil {
+		errs = append(errs, err)
+	}
+	for _, name := range []string{"card-width", "card-height"} {
+		if n := cCtx.Int(name); n < 1 {
+			errs = append(errs, fmt.Errorf("invalid %s %d", name, n))
+		}
+	}
+	for _, name := range []string{"card-background", "card-foreground", "card-accent"} {
+		if _, err := quote.ParseColor(cCtx.String(name)); err != nil {
+			errs = append(errs, fmt.Errorf("invalid %s: %w", name, err))
+		}
+	}
+	if format := cCtx.String("log-format"); format != "text" && format != "json" {
+		errs = append(errs, fmt.Errorf("unknown log-format %q", format))
+	}
+	if _, err := parseLogLevel(cCtx.String("log-level")); err != nil {
+		errs = append(errs, err)
+	}
+	for _, name := range []string{"shutdown-timeout", "tls-reload-interval", "template-reload-interval"} {
+		if d := cCtx.Duration(name); d < 0 {
+			errs = append(errs, fmt.Errorf("invalid %s %s", name, d))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// parseTrustedProxies parses the comma-separated IP addresses and CIDR
+// networks of the trusted proxies
+func parseTrustedProxies(s string) ([]netip.Prefix, error) {
+	var proxies []netip.Prefix
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if addr, err := netip.ParseAddr(field); err == nil {
+			proxies = append(proxies, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q", field)
+		}
+		proxies = append(proxies, prefix.Masked())
+	}
+	return proxies, nil
+}
+
+func newConfigCommand(flags []cli.Flag) *cli.Command {
+	cmd := &cli.Command{
+		Name:  "config",
+		Usage: "inspect the configuration",
+		Subcommands: []*cli.Command{
+			{
+				Name:        "print",
+				Usage:       "print the effective configuration in YAML and exit",
+				Description: configPrecedence,
+				Action: func(cCtx *cli.Context) error {
+					return printConfig(os.Stdout, cCtx, flags)
+				},
+			},
+		},
+	}
+	return cmd
+}
+
+// printConfig writes the effective value of flags in YAML, which can be
+// used as configuration file
+func printConfig(w io.Writer, cCtx *cli.Context, flags []cli.Flag) error {
+	doc := yaml.Node{Kind: yaml.MappingNode}
+	for _, f := range flags {
+		name := f.Names()[0]
+		value := cCtx.Value(name)
+		if d, ok := value.(time.Duration); ok {
+			value = d.String()
+		}
+		var k, v yaml.Node
+		k.SetString(name)
+		if err := v.Encode(value); err != nil {
+			return err
+		}
+		doc.Content = append(doc.Content, &k, &v)
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return err
+	}
+	return enc.Close()
+}
@@ -0,0 +1,221 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// runConfig runs Quotaday with args, calling action once the configuration
+// is loaded
+func runConfig(t *testing.T, action cli.ActionFunc, args ...string) error {
+	t.Helper()
+	flags := serverFlags()
+	app := &cli.App{
+		Flags:  append([]cli.Flag{configFlag}, flags...),
+		Before: loadConfig(flags),
+		Action: action,
+		Commands: []*cli.Command{
+			newConfigCommand(flags),
+		},
+	}
+	return app.Run(append([]string{"quotaday"}, args...))
+}
+
+// writeConfig writes a configuration file named name in a temporary
+// directory and returns its path
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfig_Precedence(t *testing.T) {
+	files := map[string]string{
+		"quotaday.yaml": "port: 8001\ndata-file: file.json\ntimezone: UTC\nmax-quotes: 30\nshutdown-timeout: 5s\nmetrics: false\n",
+		"quotaday.toml": "port = 8001\ndata-file = \"file.json\"\ntimezone = \"UTC\"\nmax-quotes = 30\nshutdown-timeout = \"5s\"\nmetrics = false\n",
+	}
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			path := writeConfig(t, name, content)
+			t.Setenv("QUOTADAY_PORT", "8002")
+			t.Setenv("QUOTADAY_DATA_FILE", "env.json")
+
+			var port uint
+			var dataFile, timezone string
+			var maxQuotes int
+			var timeout time.Duration
+			var metrics bool
+			err := runConfig(t, func(cCtx *cli.Context) error {
+				port, dataFile, timezone = cCtx.Uint("port"), cCtx.String("data-file"), cCtx.String("timezone")
+				maxQuotes, timeout, metrics = cCtx.Int("max-quotes"), cCtx.Duration("shutdown-timeout"), cCtx.Bool("metrics")
+				return nil
+			}, "--config", path, "--port", "8003")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			// Flags override the environment, which overrides the file
+			if port != 8003 {
+				t.Errorf("Expected port 8003 from the flag, got %d", port)
+			}
+			if dataFile != "env.json" {
+				t.Errorf("Expected data-file env.json from the environment, got %q", dataFile)
+			}
+			if timezone != "UTC" || maxQuotes != 30 || timeout != 5*time.Second || metrics {
+				t.Errorf("Unexpected settings from the file: timezone %q, max-quotes %d, shutdown-timeout %s, metrics %t",
+					timezone, maxQuotes, timeout, metrics)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_Defaults(t *testing.T) {
+	var port uint
+	var maxQuotes int
+	err := runConfig(t, func(cCtx *cli.Context) error {
+		port, maxQuotes = cCtx.Uint("port"), cCtx.Int("max-quotes")
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if port != 80 || maxQuotes != 20 {
+		t.Errorf("Expected the default port 80 and max-quotes 20, got %d and %d", port, maxQuotes)
+	}
+}
+
+func TestLoadConfig_InvalidFile(t *testing.T) {
+	tests := map[string]string{
+		"unknown.yaml": "port: 8001\nno-such-setting: true\n",
+		"unknown.toml": "no-such-setting = true\n",
+		"broken.yaml":  "port: [8001\n",
+		"broken.toml":  "port = \n",
+		"config.json":  `{"port": 8001}`,
+		"type.yaml":    "port: eighty\n",
+	}
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := writeConfig(t, name, content)
+			err := runConfig(t, func(*cli.Context) error {
+				t.Error("Unexpected action with an invalid config file")
+				return nil
+			}, "--config", path)
+			if err == nil {
+				t.Error("Expected error, got nil")
+			}
+		})
+	}
+
+	err := runConfig(t, nil, "--config", filepath.Join(t.TempDir(), "missing.yaml"))
+	if err == nil || !strings.Contains(err.Error(), "cannot read the config file") {
+		t.Errorf("Expected error reading a missing config file, got %v", err)
+	}
+}
+
+func TestValidateConfig(t *testing.T) {
+	path := writeConfig(t, "quotaday.yaml", "max-quotes: 0\nlog-format: xml\n")
+	t.Setenv("QUOTADAY_TIMEZONE", "Nowhere/Nothing")
+	err := runConfig(t, func(*cli.Context) error {
+		t.Error("Unexpected action with an invalid configuration")
+		return nil
+	}, "--config", path, "--tls-cert", "cert.pem", "--base-url", "ftp://example.com", "--trusted-proxies", "10.0.0.0/8,nonsense")
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+	// All the mistakes are reported at once, whichever layer they are in
+	for _, want := range []string{
+		"tls-cert and tls-key must be set together",
+		"invalid max-quotes 0",
+		`unknown log-format "xml"`,
+		"invalid timezone",
+		`invalid base URL "ftp://example.com"`,
+		`invalid trusted proxy "nonsense"`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Expected %q in error %q", want, err)
+		}
+	}
+}
+
+func TestPrintConfig(t *testing.T) {
+	printWith := func(args ...string) string {
+		t.Helper()
+		var out bytes.Buffer
+		err := runConfig(t, func(cCtx *cli.Context) error {
+			return printConfig(&out, cCtx, serverFlags())
+		}, args...)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return out.String()
+	}
+
+	printed := printWith("--port", "8080", "--shutdown-timeout", "1m30s", "--timezone", "Europe/Rome", "--metrics=false")
+	for _, want := range []string{"port: 8080\n", "shutdown-timeout: 1m30s\n", "timezone: Europe/Rome\n", "metrics: false\n", "max-quotes: 20\n"} {
+		if !strings.Contains(printed, want) {
+			t.Errorf("Expected %q in the printed configuration:\n%s", want, printed)
+		}
+	}
+
+	// The printed configuration can be used as configuration file
+	path := writeConfig(t, "quotaday.yaml", printed)
+	if again := printWith("--config", path); again != printed {
+		t.Errorf("Expected the same configuration loading the printed one, got:\n%s\ninstead of:\n%s", again, printed)
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	tests := []struct {
+		s    string
+		want []netip.Prefix
+	}{
+		{"", nil},
+		{"10.0.0.1", []netip.Prefix{netip.MustParsePrefix("10.0.0.1/32")}},
+		{" 10.1.2.3/8 , ::1", []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8"), netip.MustParsePrefix("::1/128")}},
+		{"::ffff:192.168.0.1", []netip.Prefix{netip.MustParsePrefix("192.168.0.1/32")}},
+		{"10.0.0.1,,", []netip.Prefix{netip.MustParsePrefix("10.0.0.1/32")}},
+	}
+	for _, tt := range tests {
+		got, err := parseTrustedProxies(tt.s)
+		if err != nil {
+			t.Errorf("parseTrustedProxies(%q) failed: %v", tt.s, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseTrustedProxies(%q): expected %v, got %v", tt.s, tt.want, got)
+		}
+	}
+
+	for _, s := range []string{"proxy.example.com", "10.0.0.0/33", "10.0.0.1/"} {
+		if _, err := parseTrustedProxies(s); err == nil {
+			t.Errorf("parseTrustedProxies(%q): expected error, got nil", s)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/fgday/quotaday/pkg/quote"
+)
+
+func newExportCommand() *cli.Command {
+	cmd := &cli.Command{
+		Name:      "export",
+		Usage:     "export the quotes in the configured storage to a file",
+		ArgsUsage: "[FILE] (default: standard output)",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: fmt.Sprintf("format of the file, one of %q (default: guessed from the file extension, or json)", quote.ExportFormats),
+			},
+		},
+		Action: func(cCtx *cli.Context) error {
+			if cCtx.NArg() > 1 {
+				return fmt.Errorf("expected at most one file to export to")
+			}
+			path := cCtx.Args().First()
+
+			format := quote.FormatJSON
+			var err error
+			switch {
+			case cCtx.String("format") != "":
+				format, err = quote.ParseFormat(cCtx.String("format"))
+			case path != "" && path != "-":
+				format, err = quote.FormatFromFilename(path)
+			}
+			if err != nil {
+				return err
+			}
+
+			store, err := newStore(cCtx, false, false)
+			if err != nil {
+				return err
+			}
+
+			var w io.Writer = os.Stdout
+			var f *os.File
+			if path != "" && path != "-" {
+				if f, err = os.Create(path); err != nil {
+					return err
+				}
+				defer f.Close()
+				w = f
+			}
+
+			bw := bufio.NewWriter(w)
+			if err := quote.Export(store, bw, format); err != nil {
+				return err
+			}
+			if err := bw.Flush(); err != nil {
+				return err
+			}
+			if f != nil {
+				return f.Close()
+			}
+			return nil
+		},
+	}
+	return cmd
+}
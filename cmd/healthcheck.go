@@ -0,0 +1,104 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+func newHealthcheckCommand() *cli.Command {
+	cmd := &cli.Command{
+		Name:  "healthcheck",
+		Usage: "check that the server configured by the flags is ready and exit with 0 if it is, 1 if not",
+		Description: `Meant for the HEALTHCHECK of container images without curl or wget.
+It uses the port and the TLS settings of the configuration, unless --url is set.
+When the server requires client certificates (--tls-client-ca), --cert and --key
+must be a client certificate signed by one of the allowed authorities.`,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "url",
+				Usage: "URL to check (default: the /readyz endpoint of the configured port on localhost)",
+			},
+			&cli.DurationFlag{
+				Name:  "timeout",
+				Usage: "how long to wait for the answer",
+				Value: 5 * time.Second,
+			},
+			&cli.StringFlag{
+				Name:  "cert",
+				Usage: "PEM file with the client certificate to present, along with --key",
+			},
+			&cli.StringFlag{
+				Name:  "key",
+				Usage: "PEM file with the private key of --cert",
+			},
+		},
+		Action: func(cCtx *cli.Context) error {
+			if (cCtx.String("cert") == "") != (cCtx.String("key") == "") {
+				return fmt.Errorf("--cert and --key must be set together")
+			}
+			tlsConfig := &tls.Config{}
+			if cert := cCtx.String("cert"); cert != "" {
+				pair, err := tls.LoadX509KeyPair(cert, cCtx.String("key"))
+				if err != nil {
+					return fmt.Errorf("cannot load the client certificate: %w", err)
+				}
+				tlsConfig.Certificates = []tls.Certificate{pair}
+			}
+
+			url := cCtx.String("url")
+			if url == "" {
+				scheme := "http"
+				if cCtx.String("tls-cert") != "" {
+					scheme = "https"
+				}
+				if cCtx.String("tls-client-ca") != "" && len(tlsConfig.Certificates) == 0 {
+					return fmt.Errorf("the server requires a client certificate: set --cert and --key")
+				}
+				url = fmt.Sprintf("%s://127.0.0.1:%d/readyz", scheme, cCtx.Uint("port"))
+				// The certificate is for the public name of the server,
+				// not for localhost
+				tlsConfig.InsecureSkipVerify = true
+			}
+
+			client := &http.Client{
+				Timeout: cCtx.Duration("timeout"),
+				Transport: &http.Transport{
+					TLSClientConfig: tlsConfig,
+				},
+			}
+			resp, err := client.Get(url)
+			if err != nil {
+				return cli.Exit(fmt.Sprintf("unhealthy: %s", err), 1)
+			}
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+			if resp.StatusCode != http.StatusOK {
+				return cli.Exit(fmt.Sprintf("unhealthy: %s %s", resp.Status, body), 1)
+			}
+			fmt.Printf("healthy: %s", body)
+			return nil
+		},
+	}
+	return cmd
+}
@@ -0,0 +1,108 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/fgday/quotaday/pkg/quote"
+)
+
+func newImportCommand() *cli.Command {
+	cmd := &cli.Command{
+		Name:      "import",
+		Usage:     "import quotes from a file into the configured storage, while the server is stopped",
+		ArgsUsage: "FILE (\"-\" for standard input)",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: fmt.Sprintf("format of the file, one of %q (default: guessed from the file extension)", quote.ImportFormats),
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "only check the quotes, including whether there is room for them, without importing them",
+			},
+			&cli.BoolFlag{
+				Name:  "keep-ids",
+				Usage: "keep the IDs of the quotes, e.g., to restore a backup, instead of assigning new ones (only json, jsonl and yaml files have IDs)",
+			},
+		},
+		Action: func(cCtx *cli.Context) error {
+			if cCtx.NArg() != 1 {
+				return fmt.Errorf("expected exactly one file to import")
+			}
+			path := cCtx.Args().First()
+
+			var format quote.Format
+			var err error
+			switch {
+			case cCtx.String("format") != "":
+				format, err = quote.ParseFormat(cCtx.String("format"))
+			case path == "-":
+				err = fmt.Errorf("--format is required when reading from standard input")
+			default:
+				format, err = quote.FormatFromFilename(path)
+			}
+			if err != nil {
+				return err
+			}
+
+			var r io.Reader = os.Stdin
+			if path != "-" {
+				f, err := os.Open(path)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				r = f
+			}
+
+			store, err := newStore(cCtx, !cCtx.Bool("dry-run"), false)
+			if err != nil {
+				return err
+			}
+			if _, ok := store.(*quote.FileStore); !ok && !cCtx.Bool("dry-run") {
+				return fmt.Errorf("importing quotes requires the \"file\" storage backend")
+			}
+
+			report, err := quote.Import(store, r, format, quote.ImportOptions{
+				DryRun:  cCtx.Bool("dry-run"),
+				KeepIDs: cCtx.Bool("keep-ids"),
+			})
+			if err != nil {
+				return err
+			}
+			for _, e := range report.Errors {
+				fmt.Fprintf(os.Stderr, "%s: %s\n", path, e)
+			}
+			if report.DryRun {
+				fmt.Printf("%d quotes would be imported, %d errors\n", report.Imported, len(report.Errors))
+			} else {
+				fmt.Printf("%d quotes imported, %d errors\n", report.Imported, len(report.Errors))
+			}
+			if len(report.Errors) > 0 {
+				return cli.Exit("", 1)
+			}
+			return nil
+		},
+	}
+	return cmd
+}
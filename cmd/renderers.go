@@ -0,0 +1,84 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"image/color"
+	"log/slog"
+	"os"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/fgday/quotaday/pkg/quote"
+)
+
+// newRenderers returns the quote renderers configured by the command line
+// flags. The templates are reloaded until ctx is done.
+func newRenderers(ctx context.Context, cCtx *cli.Context) (*quote.Registry, error) {
+	style := quote.DefaultCardStyle()
+	style.Width = cCtx.Int("card-width")
+	style.Height = cCtx.Int("card-height")
+
+	colors := []struct {
+		flag  string
+		color *color.RGBA
+	}{
+		{"card-background", &style.Background},
+		{"card-foreground", &style.Foreground},
+		{"card-accent", &style.Accent},
+	}
+	for _, c := range colors {
+		parsed, err := quote.ParseColor(cCtx.String(c.flag))
+		if err != nil {
+			return nil, fmt.Errorf("invalid --%s: %w", c.flag, err)
+		}
+		*c.color = parsed
+	}
+
+	if path := cCtx.String("card-font"); path != "" {
+		font, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read the card font: %w", err)
+		}
+		style.Font = font
+	}
+
+	renderers := quote.NewRegistry()
+	if err := renderers.RegisterCards(style); err != nil {
+		return nil, err
+	}
+
+	if dir := cCtx.String("template-dir"); dir != "" {
+		templates, err := quote.NewTemplates(dir)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load the templates: %w", err)
+		}
+		renderers.RegisterTemplates(templates)
+		if interval := cCtx.Duration("template-reload-interval"); interval > 0 {
+			go templates.Watch(ctx, interval, func(err error) {
+				if err != nil {
+					slog.Warn("cannot reload the templates, keeping the previous ones", "error", err)
+					return
+				}
+				slog.Info("reloaded the templates", "dir", dir)
+			})
+		}
+	}
+	return renderers, nil
+}
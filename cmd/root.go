@@ -17,10 +17,14 @@ limitations under the License.
 package cmd
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
+	"time"
+	// The container image has no timezone database
+	_ "time/tzdata"
 
 	"github.com/urfave/cli/v2"
 
@@ -28,37 +32,108 @@ import (
 )
 
 func Execute() {
+	flags := serverFlags()
 	app := &cli.App{
-		Usage: "start Quotaday webserver",
+		Usage:       "start Quotaday webserver",
+		Description: configPrecedence,
 		Commands: []*cli.Command{
 			newVersionCommand(),
+			newImportCommand(),
+			newExportCommand(),
+			newConfigCommand(flags),
+			newHealthcheckCommand(),
 		},
-		Flags: []cli.Flag{
-			&cli.UintFlag{
-				Name:    "port",
-				Aliases: []string{"p"},
-				Usage:   "port to listen to",
-				Value:   80,
-			},
-		},
+		Flags:  append([]cli.Flag{configFlag}, flags...),
+		Before: loadConfig(flags),
 		Action: func(cCtx *cli.Context) error {
 			port := fmt.Sprintf(":%d", cCtx.Uint("port"))
-			log.Printf("Starting Quotaday %s on port %s\n", versionString(), port)
+			scheme := "HTTP"
+			if cCtx.String("tls-cert") != "" {
+				scheme = "HTTPS"
+			}
+			slog.Info("starting Quotaday", "version", versionString(), "scheme", scheme, "port", cCtx.Uint("port"))
+
+			store, err := newStore(cCtx, true, true)
+			if err != nil {
+				return err
+			}
+
+			loc, err := time.LoadLocation(cCtx.String("timezone"))
+			if err != nil {
+				return fmt.Errorf("invalid timezone: %w", err)
+			}
+
+			// The files are watched until the server shuts down, not
+			// while it drains the connections
+			watchCtx, stopWatching := context.WithCancel(cCtx.Context)
+			defer stopWatching()
+
+			renderers, err := newRenderers(watchCtx, cCtx)
+			if err != nil {
+				return err
+			}
+
+			opts := []api.Option{api.WithStore(store), api.WithLocation(loc), api.WithRenderers(renderers)}
+			if base := cCtx.String("base-url"); base != "" {
+				opts = append(opts, api.WithBaseURL(base))
+			}
+			proxies, err := parseTrustedProxies(cCtx.String("trusted-proxies"))
+			if err != nil {
+				return err
+			}
+			opts = append(opts, api.WithTrustedProxies(proxies))
 
-			server := api.NewServer()
+			server := api.NewServer(opts...)
 			r := http.NewServeMux()
 			h := api.HandlerFromMux(server, r)
+			if cCtx.Bool("metrics") {
+				metrics := api.NewMetrics(store)
+				r.Handle("GET /metrics", metrics)
+				h = metrics.Middleware(h)
+			}
+			h = api.LogRequests(slog.Default(), h)
+			// Errors of the servers, e.g., failed TLS handshakes
+			errorLog := slog.NewLogLogger(slog.Default().Handler(), slog.LevelWarn)
 
 			s := &http.Server{
-				Handler: h,
-				Addr:    "0.0.0.0" + port,
+				Handler:  h,
+				Addr:     "0.0.0.0" + port,
+				ErrorLog: errorLog,
+			}
+			servers := []*http.Server{s}
+
+			if cCtx.String("tls-cert") != "" {
+				files, err := newTLSFiles(cCtx.String("tls-cert"), cCtx.String("tls-key"), cCtx.String("tls-client-ca"))
+				if err != nil {
+					return err
+				}
+				s.TLSConfig = files.serverConfig()
+				if interval := cCtx.Duration("tls-reload-interval"); interval > 0 {
+					go files.Watch(watchCtx, interval, func(err error) {
+						if err != nil {
+							slog.Warn("cannot reload the TLS files, keeping the previous ones", "error", err)
+							return
+						}
+						slog.Info("reloaded the TLS files")
+					})
+				}
+
+				if redirect := cCtx.Uint("tls-redirect-port"); redirect != 0 {
+					slog.Info("redirecting HTTP to HTTPS", "port", redirect)
+					servers = append(servers, &http.Server{
+						Handler:  redirectToHTTPS(cCtx.Uint("port")),
+						Addr:     fmt.Sprintf("0.0.0.0:%d", redirect),
+						ErrorLog: errorLog,
+					})
+				}
 			}
 
-			return s.ListenAndServe()
+			return serve(cCtx.Context, servers, store, cCtx.Duration("shutdown-timeout"), stopWatching)
 		},
 	}
 
 	if err := app.Run(os.Args); err != nil {
-		log.Fatal(err)
+		slog.Error("Quotaday failed", "error", err)
+		os.Exit(1)
 	}
 }
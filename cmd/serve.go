@@ -0,0 +1,93 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/fgday/quotaday/pkg/quote"
+)
+
+// exitUnclean is the exit code when the server was stopped but could not
+// drain the connections in time or flush the quotes
+const exitUnclean = 2
+
+// serve runs servers until SIGTERM or SIGINT is received, then stops
+// accepting connections, waits up to timeout for the requests in progress
+// and flushes store. Servers with a TLS configuration serve HTTPS.
+// A second signal stops the servers at once. shuttingDown is called before
+// draining the connections, e.g., to stop the background tasks.
+func serve(ctx context.Context, servers []*http.Server, store quote.Store, timeout time.Duration, shuttingDown func()) error {
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	failed := make(chan error, len(servers))
+	for _, s := range servers {
+		go func() {
+			if s.TLSConfig != nil {
+				failed <- s.ListenAndServeTLS("", "")
+			} else {
+				failed <- s.ListenAndServe()
+			}
+		}()
+	}
+	var errs []error
+	select {
+	case err := <-failed:
+		errs = append(errs, err)
+	case <-ctx.Done():
+	}
+	// Restore the default behavior, so that a second signal kills the servers
+	stop()
+	shuttingDown()
+
+	slog.Info("shutting down, waiting for the requests in progress", "timeout", timeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	clean := len(errs) == 0
+	for _, s := range servers {
+		if err := s.Shutdown(shutdownCtx); err != nil {
+			errs = append(errs, fmt.Errorf("cannot drain the connections: %w", err))
+			_ = s.Close()
+		}
+	}
+	if closer, ok := store.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("cannot flush the quotes: %w", err))
+		}
+	}
+	switch err := errors.Join(errs...); {
+	case !clean:
+		return err
+	case err != nil:
+		slog.Error("unclean shutdown", "error", err)
+		return cli.Exit("", exitUnclean)
+	}
+	slog.Info("Quotaday stopped")
+	return nil
+}
@@ -0,0 +1,103 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/fgday/quotaday/pkg/quote"
+)
+
+// closingStore is a Store recording whether it was closed, failing with err
+type closingStore struct {
+	*quote.QuoteBook
+	err    error
+	closed bool
+}
+
+func (s *closingStore) Close() error {
+	s.closed = true
+	return s.err
+}
+
+func TestServe(t *testing.T) {
+	tests := []struct {
+		name     string
+		closeErr error
+		exitCode int
+	}{
+		{"clean", nil, 0},
+		{"flush failure", errors.New("disk full"), exitUnclean},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			store := &closingStore{QuoteBook: quote.New(), err: tt.closeErr}
+			servers := []*http.Server{{Addr: "127.0.0.1:0", Handler: http.NotFoundHandler()}}
+			shuttingDown := false
+
+			err := serve(ctx, servers, store, time.Second, func() { shuttingDown = true })
+			if !store.closed {
+				t.Error("Expected the store to be flushed")
+			}
+			if !shuttingDown {
+				t.Error("Expected shuttingDown to be called")
+			}
+			if tt.exitCode == 0 {
+				if err != nil {
+					t.Errorf("Expected a clean shutdown, got %v", err)
+				}
+				return
+			}
+			var exit cli.ExitCoder
+			if !errors.As(err, &exit) || exit.ExitCode() != tt.exitCode {
+				t.Errorf("Expected exit code %d, got %v", tt.exitCode, err)
+			}
+		})
+	}
+}
+
+func TestServe_FailedStart(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	store := &closingStore{QuoteBook: quote.New()}
+	servers := []*http.Server{{Addr: ln.Addr().String(), Handler: http.NotFoundHandler()}}
+	err = serve(context.Background(), servers, store, time.Second, func() {})
+	if err == nil {
+		t.Fatal("Expected error serving on a port in use, got nil")
+	}
+	// The error makes Quotaday exit with 1, not as an unclean shutdown
+	var exit cli.ExitCoder
+	if errors.As(err, &exit) {
+		t.Errorf("Expected a plain error, got exit code %d", exit.ExitCode())
+	}
+	if !store.closed {
+		t.Error("Expected the store to be flushed")
+	}
+}
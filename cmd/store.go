@@ -0,0 +1,89 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/fgday/quotaday/pkg/quote"
+)
+
+// errLocked is returned when the data file is locked by another process
+var errLocked = errors.New("locked by another process")
+
+// newStore returns the quote storage backend selected by the command line
+// flags. If exclusive, the data file of the "file" backend is locked first,
+// so that the processes changing it, e.g., the server and the import
+// command, cannot overwrite each other's changes. A missing data file is
+// created with the example quotes only if create is set, as by the server.
+func newStore(cCtx *cli.Context, exclusive, create bool) (quote.Store, error) {
+	storage := cCtx.String("storage")
+	path := cCtx.String("data-file")
+	if storage == "" {
+		storage = "memory"
+		if path != "" {
+			storage = "file"
+		}
+	}
+
+	switch storage {
+	case "memory":
+		qb := quote.New()
+		qb.FillExample()
+		qb.SetMaxQuotes(cCtx.Int("max-quotes"))
+		return qb, nil
+	case "file":
+		if path == "" {
+			return nil, fmt.Errorf("the \"file\" storage backend requires --data-file")
+		}
+		notFound := fmt.Errorf("data file %s not found: check --data-file, or start the server to create it", path)
+		if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) && !create {
+			// Not even the lock file is created
+			return nil, notFound
+		}
+		if exclusive {
+			lock := filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".lock")
+			if err := lockFile(lock); errors.Is(err, errLocked) {
+				return nil, fmt.Errorf("%s is in use by another quotaday process, e.g., the server: stop it first", path)
+			} else if err != nil {
+				return nil, fmt.Errorf("cannot lock %s: %w", path, err)
+			}
+		}
+		open := quote.OpenFileStore
+		if create {
+			open = quote.NewFileStore
+		}
+		store, err := open(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, notFound
+		} else if err != nil {
+			return nil, fmt.Errorf("cannot load quotes from %s: %w", path, err)
+		}
+		store.SetMaxQuotes(cCtx.Int("max-quotes"))
+		slog.Info("using data file", "path", path)
+		return store, nil
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", storage)
+	}
+}
@@ -0,0 +1,180 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tlsFiles are the certificate, key and client CAs HTTPS is served with,
+// which are loaded again when they change on disk, e.g., when certificates
+// are rotated
+type tlsFiles struct {
+	cert     string
+	key      string
+	clientCA string
+
+	mu sync.RWMutex
+	// config is the configuration of new connections
+	config *tls.Config
+	// stamp identifies the version of the files config was loaded from
+	stamp string
+}
+
+// newTLSFiles loads the certificate and key files, along with the client
+// CAs file if not empty
+func newTLSFiles(cert, key, clientCA string) (*tlsFiles, error) {
+	t := &tlsFiles{cert: cert, key: key, clientCA: clientCA}
+	if _, err := t.Reload(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Reload loads the files again if they changed since they were last loaded,
+// and tells whether they did. On errors the previous files are kept.
+func (t *tlsFiles) Reload() (bool, error) {
+	stamp, err := t.currentStamp()
+	if err != nil {
+		return false, err
+	}
+	t.mu.RLock()
+	unchanged := t.config != nil && stamp == t.stamp
+	t.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	config, err := t.load()
+	if err != nil {
+		return false, err
+	}
+	t.mu.Lock()
+	t.config, t.stamp = config, stamp
+	t.mu.Unlock()
+	return true, nil
+}
+
+// Watch reloads the files whenever they change, checking them at every
+// interval until ctx is done. If onReload is not nil, it is called after
+// each attempt to reload the files with its error.
+func (t *tlsFiles) Watch(ctx context.Context, interval time.Duration, onReload func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reloaded, err := t.Reload()
+			if (reloaded || err != nil) && onReload != nil {
+				onReload(err)
+			}
+		}
+	}
+}
+
+// currentStamp returns a string identifying the current version of the files
+func (t *tlsFiles) currentStamp() (string, error) {
+	var stamp bytes.Buffer
+	for _, path := range []string{t.cert, t.key, t.clientCA} {
+		if path == "" {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&stamp, "%s:%d:%d;", path, info.Size(), info.ModTime().UnixNano())
+	}
+	return stamp.String(), nil
+}
+
+// load returns the TLS configuration with the current files
+func (t *tlsFiles) load() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(t.cert, t.key)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load the TLS certificate: %w", err)
+	}
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// Only forward secret AEAD ciphers, TLS 1.3 ones are always enabled
+		CipherSuites: []uint16{
+			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+		},
+		CurvePreferences: []tls.CurveID{tls.X25519, tls.CurveP256, tls.CurveP384},
+		Certificates:     []tls.Certificate{cert},
+		NextProtos:       []string{"h2", "http/1.1"},
+	}
+	if t.clientCA != "" {
+		pem, err := os.ReadFile(t.clientCA)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read the client CAs: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in %s", t.clientCA)
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return config, nil
+}
+
+// serverConfig returns the TLS configuration of a server, which picks the
+// files loaded last for each new connection
+func (t *tlsFiles) serverConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			t.mu.RLock()
+			defer t.mu.RUnlock()
+			return t.config, nil
+		},
+	}
+}
+
+// redirectToHTTPS returns the handler redirecting requests to the same URL
+// served over HTTPS on port
+func redirectToHTTPS(port uint) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			host = strings.Trim(r.Host, "[]")
+		}
+		if port != 443 {
+			host = net.JoinHostPort(host, strconv.FormatUint(uint64(port), 10))
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusPermanentRedirect)
+	})
+}
@@ -0,0 +1,201 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// testCert is a self-signed certificate, which can also sign client
+// certificates, with its key
+type testCert struct {
+	der     []byte
+	certPEM []byte
+	keyPEM  []byte
+}
+
+func newTestCert(t *testing.T, name string) *testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1)},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCert{
+		der:     der,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+// write writes the certificate and key to the files at the given paths,
+// changing their modification time so that they are seen as changed
+func (c *testCert) write(t *testing.T, certPath, keyPath string, modTime time.Time) {
+	t.Helper()
+	for path, data := range map[string][]byte{certPath: c.certPEM, keyPath: c.keyPEM} {
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// servedCert returns the DER certificate served to new connections
+func servedCert(t *testing.T, files *tlsFiles) []byte {
+	t.Helper()
+	config, err := files.serverConfig().GetConfigForClient(&tls.ClientHelloInfo{})
+	if err != nil {
+		t.Fatalf("GetConfigForClient failed: %v", err)
+	}
+	return config.Certificates[0].Certificate[0]
+}
+
+func TestTLSFiles_Reload(t *testing.T) {
+	dir := t.TempDir()
+	certPath, keyPath := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	first, second := newTestCert(t, "first"), newTestCert(t, "second")
+	start := time.Now().Add(-time.Minute)
+	first.write(t, certPath, keyPath, start)
+
+	files, err := newTLSFiles(certPath, keyPath, "")
+	if err != nil {
+		t.Fatalf("newTLSFiles failed: %v", err)
+	}
+	if !bytes.Equal(servedCert(t, files), first.der) {
+		t.Fatal("Expected the first certificate to be served")
+	}
+	if reloaded, err := files.Reload(); reloaded || err != nil {
+		t.Errorf("Expected no reload of unchanged files, got %t, %v", reloaded, err)
+	}
+
+	// A rotated certificate is served to new connections
+	second.write(t, certPath, keyPath, start.Add(time.Second))
+	if reloaded, err := files.Reload(); !reloaded || err != nil {
+		t.Fatalf("Expected the rotated files to be reloaded, got %t, %v", reloaded, err)
+	}
+	if !bytes.Equal(servedCert(t, files), second.der) {
+		t.Error("Expected the rotated certificate to be served")
+	}
+
+	// Broken files keep the previous certificate
+	if err := os.WriteFile(certPath, []byte("garbage"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if reloaded, err := files.Reload(); reloaded || err == nil {
+		t.Errorf("Expected error reloading a broken certificate, got %t, %v", reloaded, err)
+	}
+	if !bytes.Equal(servedCert(t, files), second.der) {
+		t.Error("Expected the previous certificate to be kept")
+	}
+	if err := os.Remove(keyPath); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := files.Reload(); err == nil {
+		t.Error("Expected error reloading a missing key, got nil")
+	}
+	if !bytes.Equal(servedCert(t, files), second.der) {
+		t.Error("Expected the previous certificate to be kept")
+	}
+}
+
+func TestTLSFiles_ClientCA(t *testing.T) {
+	dir := t.TempDir()
+	certPath, keyPath, caPath := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem"), filepath.Join(dir, "ca.pem")
+	server, client := newTestCert(t, "server"), newTestCert(t, "client")
+	server.write(t, certPath, keyPath, time.Now())
+	// The client certificate is self-signed, so it is its own CA
+	if err := os.WriteFile(caPath, client.certPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newTLSFiles(certPath, keyPath, certPath+".missing"); err == nil {
+		t.Error("Expected error with missing client CAs, got nil")
+	}
+	files, err := newTLSFiles(certPath, keyPath, caPath)
+	if err != nil {
+		t.Fatalf("newTLSFiles failed: %v", err)
+	}
+
+	s := httptest.NewUnstartedServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	s.TLS = files.serverConfig()
+	s.StartTLS()
+	defer s.Close()
+
+	roots := x509.NewCertPool()
+	roots.AppendCertsFromPEM(server.certPEM)
+	get := func(certs ...tls.Certificate) error {
+		c := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots, Certificates: certs}}}
+		resp, err := c.Get(s.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	if err := get(); err == nil {
+		t.Error("Expected a client without certificate to be rejected")
+	}
+	other := newTestCert(t, "other")
+	otherPair, err := tls.X509KeyPair(other.certPEM, other.keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := get(otherPair); err == nil {
+		t.Error("Expected a client certificate from another CA to be rejected")
+	}
+	pair, err := tls.X509KeyPair(client.certPEM, client.keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := get(pair); err != nil {
+		t.Errorf("Expected a client certificate from the CA to be accepted, got %v", err)
+	}
+}
@@ -3,8 +3,12 @@ module github.com/fgday/quotaday
 go 1.24.1
 
 require (
+	github.com/BurntSushi/toml v1.4.0
 	github.com/oapi-codegen/runtime v1.1.1
 	github.com/urfave/cli/v2 v2.27.6
+	golang.org/x/image v0.18.0
+	golang.org/x/text v0.18.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -26,10 +30,8 @@ require (
 	github.com/vmware-labs/yaml-jsonpath v0.3.2 // indirect
 	github.com/xrash/smetrics v0.0.0-20240521201337-686a1a2994c1 // indirect
 	golang.org/x/mod v0.17.0 // indirect
-	golang.org/x/text v0.18.0 // indirect
 	golang.org/x/tools v0.21.1-0.20240508182429-e35e4ccd0d2d // indirect
 	gopkg.in/yaml.v2 v2.4.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
 
 tool github.com/oapi-codegen/oapi-codegen/v2/cmd/oapi-codegen
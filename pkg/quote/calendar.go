@@ -0,0 +1,119 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quote
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// icsLineLength is the maximum length in octets of the lines of an
+// iCalendar object, as defined in RFC 5545, section 3.1
+const icsLineLength = 75
+
+// Calendar is the calendar of the quotes of the upcoming days, which can
+// be written in the iCalendar format
+type Calendar struct {
+	// BaseURL is the URL the service is reachable at, used to link the
+	// quotes and to identify the events
+	BaseURL string
+	// Created is the time the Calendar was created
+	Created time.Time
+	// Entries are the quotes of the day, in chronological order
+	Entries []DailyEntry
+}
+
+// NewCalendar returns the Calendar of the quotes of the day of the given
+// number of days, starting from the calendar day of t in its location.
+// The Calendar is considered created at the beginning of that day, so
+// that it does not change during the day. The quotes of the following days
+// are not pinned yet, so they can change until those days are reached.
+func NewCalendar(store Store, baseURL string, t time.Time, days int) (*Calendar, error) {
+	entries, err := DailyQuotations(store, t, days, t)
+	if err != nil {
+		return nil, err
+	}
+	y, m, d := t.Date()
+	created := time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+	return &Calendar{BaseURL: baseURL, Created: created, Entries: entries}, nil
+}
+
+// WriteICS writes c as an iCalendar object, as defined in RFC 5545, with
+// an all-day event for each quote of the day
+func (c *Calendar) WriteICS(w io.Writer) error {
+	var b strings.Builder
+	line := func(name, value string) {
+		writeICSLine(&b, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//Quotaday//Quote of the day//EN")
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	line("X-WR-CALNAME", "Quote of the day")
+	stamp := c.Created.UTC().Format("20060102T150405Z")
+	for _, e := range c.Entries {
+		link := c.BaseURL + "/quotes/" + strconv.Itoa(e.Quote.ID)
+		line("BEGIN", "VEVENT")
+		line("UID", e.Day.Format(time.DateOnly)+"-daily@"+hostname(c.BaseURL))
+		line("DTSTAMP", stamp)
+		line("DTSTART;VALUE=DATE", e.Day.Format("20060102"))
+		line("DTEND;VALUE=DATE", NextDay(e.Day).Format("20060102"))
+		line("SUMMARY", escapeICSText(entryText(&e.Quote)))
+		line("DESCRIPTION", escapeICSText(entryText(&e.Quote)+"\n\n"+link))
+		line("URL", link)
+		line("TRANSP", "TRANSPARENT")
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// escapeICSText escapes the characters with a special meaning in the
+// TEXT values of an iCalendar object
+func escapeICSText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// writeICSLine writes a content line terminated by CRLF, folding it in
+// lines no longer than icsLineLength octets without splitting characters
+func writeICSLine(b *strings.Builder, s string) {
+	limit := icsLineLength
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		b.WriteString(s[:cut] + "\r\n ")
+		s = s[cut:]
+		// Continuation lines start with a space
+		limit = icsLineLength - 1
+	}
+	b.WriteString(s + "\r\n")
+}
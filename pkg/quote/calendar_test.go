@@ -0,0 +1,96 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quote
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewCalendar(t *testing.T) {
+	qb := New()
+	qb.FillExample()
+	loc := time.FixedZone("UTC+1", 60*60)
+	c, err := NewCalendar(qb, "https://quote.example.com", time.Date(2025, 3, 30, 18, 0, 0, 0, loc), 3)
+	if err != nil {
+		t.Fatalf("NewCalendar failed: %v", err)
+	}
+	if want := time.Date(2025, 3, 30, 0, 0, 0, 0, loc); !c.Created.Equal(want) {
+		t.Errorf("Expected the calendar created at %v, got %v", want, c.Created)
+	}
+
+	var buf bytes.Buffer
+	if err := c.WriteICS(&buf); err != nil {
+		t.Fatalf("WriteICS failed: %v", err)
+	}
+	ics := buf.String()
+	if !strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n") || !strings.HasSuffix(ics, "END:VCALENDAR\r\n") {
+		t.Errorf("Unexpected calendar:\n%s", ics)
+	}
+	if n := strings.Count(ics, "BEGIN:VEVENT\r\n"); n != 3 {
+		t.Errorf("Expected 3 events, got %d", n)
+	}
+	for _, want := range []string{
+		"DTSTAMP:20250329T230000Z\r\n",
+		"UID:2025-03-31-daily@quote.example.com\r\n",
+		"DTSTART;VALUE=DATE:20250401\r\nDTEND;VALUE=DATE:20250402\r\n",
+	} {
+		if !strings.Contains(ics, want) {
+			t.Errorf("Expected %q in calendar:\n%s", want, ics)
+		}
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(ics, "\r\n"), "\r\n") {
+		if len(line) > icsLineLength {
+			t.Errorf("Line longer than %d octets: %q", icsLineLength, line)
+		}
+	}
+}
+
+func TestEscapeICSText(t *testing.T) {
+	got := escapeICSText("One, two; three\\four\nfive")
+	want := `One\, two\; three\\four\nfive`
+	if got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestWriteICSLine(t *testing.T) {
+	var b strings.Builder
+	long := "SUMMARY:" + strings.Repeat("è", 100)
+	writeICSLine(&b, long)
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n")
+	if len(lines) < 3 {
+		t.Fatalf("Expected the line to be folded, got %q", lines)
+	}
+	unfolded := lines[0]
+	for _, line := range lines[1:] {
+		if !strings.HasPrefix(line, " ") {
+			t.Errorf("Expected continuation line to start with a space, got %q", line)
+		}
+		unfolded += line[1:]
+	}
+	for _, line := range lines {
+		if len(line) > icsLineLength {
+			t.Errorf("Line longer than %d octets: %q", icsLineLength, line)
+		}
+	}
+	if unfolded != long {
+		t.Errorf("Expected %q after unfolding, got %q", long, unfolded)
+	}
+}
@@ -0,0 +1,289 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quote
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"strings"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	// minCardFontSize is the smallest size, in pixels, of the quote text
+	// on a card: longer quotes overflow the card
+	minCardFontSize = 12
+	// cardLineSpacing is the distance between lines relative to the font size
+	cardLineSpacing = 1.3
+	// cardAuthorScale is the size of the author relative to the quote
+	cardAuthorScale = 0.6
+)
+
+// CardStyle sets the look of the image cards quotes are rendered on
+type CardStyle struct {
+	// Width and Height are the size of the card in pixels
+	Width  int
+	Height int
+	// Background, Foreground and Accent are the colors of the card,
+	// of the quote and of the author
+	Background color.RGBA
+	Foreground color.RGBA
+	Accent     color.RGBA
+	// Font is a TrueType or OpenType font, the Go font is used if nil
+	Font []byte
+}
+
+// DefaultCardStyle returns the CardStyle used by the built-in Renderers:
+// the size suggested for social network previews, with light text on a
+// dark background
+func DefaultCardStyle() CardStyle {
+	return CardStyle{
+		Width:      1200,
+		Height:     630,
+		Background: color.RGBA{0x1e, 0x29, 0x3b, 0xff},
+		Foreground: color.RGBA{0xf8, 0xfa, 0xfc, 0xff},
+		Accent:     color.RGBA{0xfb, 0xbf, 0x24, 0xff},
+	}
+}
+
+// ParseColor parses a color in the "#rrggbb" or "#rgb" hexadecimal notation
+func ParseColor(s string) (color.RGBA, error) {
+	c := color.RGBA{A: 0xff}
+	var err error
+	switch len(s) {
+	case 7:
+		_, err = fmt.Sscanf(s, "#%02x%02x%02x", &c.R, &c.G, &c.B)
+	case 4:
+		_, err = fmt.Sscanf(s, "#%1x%1x%1x", &c.R, &c.G, &c.B)
+		c.R, c.G, c.B = c.R*0x11, c.G*0x11, c.B*0x11
+	default:
+		err = errors.New("expected the #rrggbb or #rgb notation")
+	}
+	if err != nil {
+		return c, fmt.Errorf("invalid color %q: %w", s, err)
+	}
+	return c, nil
+}
+
+// hexColor returns c in the "#rrggbb" notation
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// card lays out quotes on images according to a CardStyle
+type card struct {
+	style CardStyle
+	font  *opentype.Font
+	// fontData is the font embedded in SVG images
+	fontData []byte
+}
+
+// cardLine is a line of text placed on a card
+type cardLine struct {
+	text string
+	// x is the horizontal center of the line, y its baseline
+	x, y   int
+	size   float64
+	accent bool
+	face   font.Face
+}
+
+func newCard(style CardStyle) (*card, error) {
+	if style.Width < 100 || style.Height < 100 || style.Width > 4096 || style.Height > 4096 {
+		return nil, fmt.Errorf("invalid card size %dx%d: must be between 100 and 4096 pixels", style.Width, style.Height)
+	}
+	fontData := style.Font
+	if fontData == nil {
+		fontData = goregular.TTF
+	}
+	f, err := opentype.Parse(fontData)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse the card font: %w", err)
+	}
+	return &card{style: style, font: f, fontData: fontData}, nil
+}
+
+// face returns the font at the given size in pixels. Faces are not
+// safe for concurrent use, so a new one is returned at each call.
+func (c *card) face(size float64) (font.Face, error) {
+	return opentype.NewFace(c.font, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+}
+
+// layout wraps the quote and the author of q with the biggest font fitting
+// the card and returns the lines centered on it
+func (c *card) layout(q *Quotation) ([]cardLine, error) {
+	padX, padY := c.style.Width/12, c.style.Height/10
+	areaW, areaH := c.style.Width-2*padX, c.style.Height-2*padY
+	text := "“" + strings.TrimSpace(q.Quote) + "”"
+	author := ""
+	if q.Author != "" {
+		author = "— " + q.Author
+	}
+
+	var lines []cardLine
+	for size := float64(c.style.Height) / 7; ; size *= 0.9 {
+		size = max(size, minCardFontSize)
+		quoteFace, err := c.face(size)
+		if err != nil {
+			return nil, err
+		}
+		authorFace, err := c.face(size * cardAuthorScale)
+		if err != nil {
+			return nil, err
+		}
+		fits := func(face font.Face) func(string) bool {
+			return func(line string) bool {
+				return font.MeasureString(face, line).Ceil() <= areaW
+			}
+		}
+
+		lines = lines[:0]
+		wide := false
+		for _, line := range wrapWords(text, fits(quoteFace)) {
+			lines = append(lines, cardLine{text: line, size: size, face: quoteFace})
+			wide = wide || !fits(quoteFace)(line)
+		}
+		if author != "" {
+			for _, line := range wrapWords(author, fits(authorFace)) {
+				lines = append(lines, cardLine{text: line, size: size * cardAuthorScale, accent: true, face: authorFace})
+				wide = wide || !fits(authorFace)(line)
+			}
+		}
+
+		height := 0.0
+		for i, line := range lines {
+			height += line.size * cardLineSpacing
+			// Leave some room between the quote and the author
+			if line.accent && (i == 0 || !lines[i-1].accent) {
+				height += size * 0.5
+			}
+		}
+		if (!wide && height <= float64(areaH)) || size == minCardFontSize {
+			// Center the text block vertically
+			y := float64(c.style.Height)/2 - height/2
+			for i := range lines {
+				if lines[i].accent && (i == 0 || !lines[i-1].accent) {
+					y += size * 0.5
+				}
+				ascent := float64(lines[i].face.Metrics().Ascent.Ceil())
+				lineHeight := lines[i].size * cardLineSpacing
+				lines[i].x = c.style.Width / 2
+				lines[i].y = int(y + (lineHeight-lines[i].size)/2 + ascent)
+				y += lineHeight
+			}
+			return lines, nil
+		}
+	}
+}
+
+// PNGRenderer writes quotes as PNG images of cards
+type PNGRenderer struct {
+	card *card
+}
+
+// NewPNGRenderer returns a PNGRenderer drawing cards with the given style
+func NewPNGRenderer(style CardStyle) (*PNGRenderer, error) {
+	c, err := newCard(style)
+	if err != nil {
+		return nil, err
+	}
+	return &PNGRenderer{card: c}, nil
+}
+
+func (r *PNGRenderer) RenderQuote(w io.Writer, q *Quotation, _ RenderOptions) error {
+	lines, err := r.card.layout(q)
+	if err != nil {
+		return err
+	}
+
+	style := r.card.style
+	img := image.NewRGBA(image.Rect(0, 0, style.Width, style.Height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(style.Background), image.Point{}, draw.Src)
+	for _, line := range lines {
+		src := style.Foreground
+		if line.accent {
+			src = style.Accent
+		}
+		d := font.Drawer{Dst: img, Src: image.NewUniform(src), Face: line.face}
+		width := d.MeasureString(line.text)
+		d.Dot = fixed.Point26_6{X: fixed.I(line.x) - width/2, Y: fixed.I(line.y)}
+		d.DrawString(line.text)
+	}
+	return png.Encode(w, img)
+}
+
+// SVGRenderer writes quotes as SVG images of cards, embedding the font
+// so that they look the same everywhere
+type SVGRenderer struct {
+	card *card
+	// fontFace is the CSS rule defining the embedded font
+	fontFace string
+}
+
+// NewSVGRenderer returns a SVGRenderer drawing cards with the given style
+func NewSVGRenderer(style CardStyle) (*SVGRenderer, error) {
+	c, err := newCard(style)
+	if err != nil {
+		return nil, err
+	}
+	fontFace := fmt.Sprintf("@font-face{font-family:quotaday;src:url(data:font/ttf;base64,%s)}",
+		base64.StdEncoding.EncodeToString(c.fontData))
+	return &SVGRenderer{card: c, fontFace: fontFace}, nil
+}
+
+func (r *SVGRenderer) RenderQuote(w io.Writer, q *Quotation, _ RenderOptions) error {
+	lines, err := r.card.layout(q)
+	if err != nil {
+		return err
+	}
+
+	style := r.card.style
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		style.Width, style.Height, style.Width, style.Height)
+	fmt.Fprintf(&b, "<style>%s text{font-family:quotaday,sans-serif;text-anchor:middle}</style>\n", r.fontFace)
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", hexColor(style.Background))
+	for _, line := range lines {
+		fill := style.Foreground
+		if line.accent {
+			fill = style.Accent
+		}
+		fmt.Fprintf(&b, `<text x="%d" y="%d" font-size="%.1f" fill="%s">%s</text>`+"\n",
+			line.x, line.y, line.size, hexColor(fill), escapeXML(line.text))
+	}
+	b.WriteString("</svg>\n")
+	_, err = io.WriteString(w, b.String())
+	return err
+}
+
+// escapeXML escapes the characters with a special meaning in XML text
+func escapeXML(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
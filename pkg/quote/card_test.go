@@ -0,0 +1,133 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quote
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestParseColor(t *testing.T) {
+	for s, want := range map[string]color.RGBA{
+		"#1e293b": {0x1e, 0x29, 0x3b, 0xff},
+		"#FFF":    {0xff, 0xff, 0xff, 0xff},
+		"#0a0":    {0x00, 0xaa, 0x00, 0xff},
+	} {
+		if got, err := ParseColor(s); err != nil || got != want {
+			t.Errorf("ParseColor(%q) = %v, %v, expected %v", s, got, err, want)
+		}
+	}
+	for _, s := range []string{"", "red", "#12345", "123456", "#gggggg"} {
+		if _, err := ParseColor(s); err == nil {
+			t.Errorf("Expected error parsing %q, got nil", s)
+		}
+	}
+}
+
+func TestNewCard_Invalid(t *testing.T) {
+	style := DefaultCardStyle()
+	style.Width = 10
+	if _, err := NewPNGRenderer(style); err == nil {
+		t.Error("Expected error for a too small card, got nil")
+	}
+	style = DefaultCardStyle()
+	style.Font = []byte("not a font")
+	if _, err := NewSVGRenderer(style); err == nil {
+		t.Error("Expected error for an invalid font, got nil")
+	}
+}
+
+func TestCardLayout(t *testing.T) {
+	c, err := newCard(DefaultCardStyle())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, q := range []Quotation{
+		{Quote: "Short", Author: "Tester"},
+		{Quote: strings.Repeat("A rather long quote to be wrapped on many lines. ", 20)},
+	} {
+		lines, err := c.layout(&q)
+		if err != nil {
+			t.Fatalf("layout failed: %v", err)
+		}
+		if len(lines) == 0 {
+			t.Fatal("Expected some lines")
+		}
+		for i, line := range lines {
+			if line.y <= 0 || line.y > c.style.Height {
+				t.Errorf("Line %q is out of the card at y=%d", line.text, line.y)
+			}
+			if i > 0 && line.y <= lines[i-1].y {
+				t.Errorf("Line %q is not below the previous one", line.text)
+			}
+		}
+		if q.Author != "" && !lines[len(lines)-1].accent {
+			t.Error("Expected the author on the last line")
+		}
+	}
+}
+
+func TestPNGRenderer(t *testing.T) {
+	style := DefaultCardStyle()
+	style.Width, style.Height = 600, 300
+	r, err := NewPNGRenderer(style)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	q := Quotation{Quote: "Eat the frog first.", Author: "Brian Tracy"}
+	if err := r.RenderQuote(&buf, &q, RenderOptions{}); err != nil {
+		t.Fatalf("RenderQuote failed: %v", err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("Invalid PNG: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 600 || b.Dy() != 300 {
+		t.Errorf("Expected a 600x300 image, got %v", b)
+	}
+	if got := color.RGBAModel.Convert(img.At(0, 0)); got != style.Background {
+		t.Errorf("Expected background %v, got %v", style.Background, got)
+	}
+}
+
+func TestSVGRenderer(t *testing.T) {
+	r, err := NewSVGRenderer(DefaultCardStyle())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	q := Quotation{Quote: "Less <is> more & more", Author: "Tester"}
+	if err := r.RenderQuote(&buf, &q, RenderOptions{}); err != nil {
+		t.Fatalf("RenderQuote failed: %v", err)
+	}
+	svg := buf.String()
+	for _, want := range []string{
+		`width="1200" height="630"`,
+		"@font-face{font-family:quotaday;src:url(data:font/ttf;base64,",
+		`fill="#1e293b"`,
+		"“Less &lt;is&gt; more &amp;",
+		`fill="#fbbf24">— Tester</text>`,
+	} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("Expected %q in SVG output", want)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quote
+
+import (
+	"fmt"
+	"hash/fnv"
+	"time"
+)
+
+// DailyQuotation returns the quote of the day for the calendar day of t in
+// its location: every call for the same day returns the same quote, even
+// if other quotes are added or deleted in the meantime.
+func DailyQuotation(store Store, t time.Time) (*Quotation, error) {
+	quotes, err := store.PinnedQuotes([]string{t.Format(time.DateOnly)}, chooseDaily)
+	if err != nil {
+		return nil, err
+	}
+	return &quotes[0], nil
+}
+
+// chooseDaily chooses the position of the quote of the day among count
+// quotes, when it is first requested: day is the calendar day
+func chooseDaily(day string, count int) int {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(day))
+	return int(h.Sum64() % uint64(count))
+}
+
+// previewDaily returns the quote chooseDaily picks for day among the
+// stored quotes, without pinning it
+func previewDaily(store Store, day string) (*Quotation, error) {
+	for {
+		count := store.Count()
+		if count == 0 {
+			return nil, fmt.Errorf("%w: empty store", ErrNotFound)
+		}
+		quotes, err := store.ListQuotes(chooseDaily(day, count), 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(quotes) == 1 {
+			return &quotes[0], nil
+		}
+		// Quotes were deleted in the meantime
+	}
+}
+
+// NextDay returns the beginning of the calendar day following t,
+// in the location of t
+func NextDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d+1, 0, 0, 0, 0, t.Location())
+}
+
+// DailyEntry is the quote of the day of a calendar day
+type DailyEntry struct {
+	// Day is the beginning of the calendar day
+	Day   time.Time
+	Quote Quotation
+}
+
+// DailyQuotations returns the quotes of the day of the given number of
+// consecutive days, starting from the calendar day of from in its location.
+// The quotes of the days up to the one of now are the ones DailyQuotation
+// returns, while the ones of the later days are not pinned yet: they can
+// change, e.g., when quotes are added before those days.
+// No entries are returned if store is empty.
+func DailyQuotations(store Store, from time.Time, days int, now time.Time) ([]DailyEntry, error) {
+	if store.Count() == 0 {
+		return nil, nil
+	}
+
+	y, m, d := from.Date()
+	today := now.In(from.Location()).Format(time.DateOnly)
+	entries := make([]DailyEntry, 0, days)
+	var reached []string
+	for i := range days {
+		day := time.Date(y, m, d+i, 0, 0, 0, 0, from.Location())
+		entries = append(entries, DailyEntry{Day: day})
+		if key := day.Format(time.DateOnly); key <= today {
+			reached = append(reached, key)
+		}
+	}
+	// All the days reached are pinned at once
+	if len(reached) > 0 {
+		quotes, err := store.PinnedQuotes(reached, chooseDaily)
+		if err != nil {
+			return nil, err
+		}
+		for i := range quotes {
+			entries[i].Quote = quotes[i]
+		}
+	}
+	for i := len(reached); i < len(entries); i++ {
+		q, err := previewDaily(store, entries[i].Day.Format(time.DateOnly))
+		if err != nil {
+			return nil, err
+		}
+		entries[i].Quote = *q
+	}
+	return entries, nil
+}
@@ -0,0 +1,208 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quote
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDailyQuotation(t *testing.T) {
+	qb := New()
+	if _, err := DailyQuotation(qb, time.Now()); err == nil {
+		t.Error("Expected error on empty QuoteBook, got nil")
+	}
+
+	qb.FillExample()
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	morning := time.Date(2025, 3, 14, 0, 30, 0, 0, loc)
+	evening := time.Date(2025, 3, 14, 23, 59, 0, 0, loc)
+	first, err := DailyQuotation(qb, morning)
+	if err != nil {
+		t.Fatalf("DailyQuotation failed: %v", err)
+	}
+	second, err := DailyQuotation(qb, evening)
+	if err != nil {
+		t.Fatalf("DailyQuotation failed: %v", err)
+	}
+	if !reflect.DeepEqual(*first, *second) {
+		t.Errorf("Expected the same quote all day long, got %+v and %+v", first, second)
+	}
+
+	// The quote must change at least once in a week
+	changed := false
+	for d := 1; d < 7; d++ {
+		q, err := DailyQuotation(qb, morning.AddDate(0, 0, d))
+		if err != nil {
+			t.Fatalf("DailyQuotation failed: %v", err)
+		}
+		if !reflect.DeepEqual(*q, *first) {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		t.Error("Expected a different quote in the following days")
+	}
+}
+
+func TestDailyQuotation_Pinned(t *testing.T) {
+	qb := New()
+	qb.FillExample()
+	today := time.Date(2025, 3, 14, 10, 0, 0, 0, time.UTC)
+	want, err := DailyQuotation(qb, today)
+	if err != nil {
+		t.Fatalf("DailyQuotation failed: %v", err)
+	}
+	past, err := DailyQuotations(qb, today.AddDate(0, 0, -9), 10, today)
+	if err != nil {
+		t.Fatalf("DailyQuotations failed: %v", err)
+	}
+
+	// Adding and deleting other quotes does not change the quotes of the day
+	for range 5 {
+		if _, err := qb.AddQuote(Quotation{Quote: "Added during the day"}); err != nil {
+			t.Fatalf("AddQuote failed: %v", err)
+		}
+	}
+	for _, e := range past {
+		if e.Quote.ID != want.ID {
+			_ = qb.DeleteQuote(e.Quote.ID)
+			break
+		}
+	}
+	got, err := DailyQuotation(qb, today)
+	if err != nil {
+		t.Fatalf("DailyQuotation failed: %v", err)
+	}
+	if !reflect.DeepEqual(*got, *want) {
+		t.Errorf("Expected the quote of the day %+v unchanged, got %+v", *want, *got)
+	}
+	entries, err := DailyQuotations(qb, today.AddDate(0, 0, -9), 10, today)
+	if err != nil {
+		t.Fatalf("DailyQuotations failed: %v", err)
+	}
+	for i, e := range entries {
+		if _, err := qb.GetQuote(past[i].Quote.ID); err == nil && e.Quote.ID != past[i].Quote.ID {
+			t.Errorf("Expected the quote %d on %s, got %d", past[i].Quote.ID, e.Day, e.Quote.ID)
+		}
+	}
+
+	// A deleted quote of the day is replaced
+	if err := qb.DeleteQuote(want.ID); err != nil {
+		t.Fatalf("DeleteQuote failed: %v", err)
+	}
+	if got, err := DailyQuotation(qb, today); err != nil || got.ID == want.ID {
+		t.Errorf("Expected another quote of the day, got %+v, %v", got, err)
+	}
+}
+
+func TestDailyQuotations_Future(t *testing.T) {
+	qb := New()
+	qb.FillExample()
+	today := time.Date(2025, 3, 14, 10, 0, 0, 0, time.UTC)
+	entries, err := DailyQuotations(qb, today, 30, today)
+	if err != nil {
+		t.Fatalf("DailyQuotations failed: %v", err)
+	}
+	if len(entries) != 30 {
+		t.Fatalf("Expected 30 entries, got %d", len(entries))
+	}
+
+	// Only the days reached are pinned
+	if want := map[string]int{"2025-03-14": entries[0].Quote.ID}; !reflect.DeepEqual(qb.pins, want) {
+		t.Errorf("Expected pins %v, got %v", want, qb.pins)
+	}
+	// The later quotes of the day are the ones pinned when reached
+	for _, e := range entries[1:] {
+		q, err := DailyQuotation(qb, e.Day)
+		if err != nil {
+			t.Fatalf("DailyQuotation failed: %v", err)
+		}
+		if q.ID != e.Quote.ID {
+			t.Errorf("Expected the quote %d on %s, got %d", e.Quote.ID, e.Day, q.ID)
+		}
+	}
+}
+
+func TestPinnedQuotes_Limit(t *testing.T) {
+	qb := New()
+	qb.FillExample()
+	keys := make([]string, maxPins+10)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("%05d", i)
+	}
+	if _, err := qb.PinnedQuotes(keys, chooseDaily); err != nil {
+		t.Fatalf("PinnedQuotes failed: %v", err)
+	}
+	if len(qb.pins) != maxPins {
+		t.Errorf("Expected %d pins, got %d", maxPins, len(qb.pins))
+	}
+	if _, ok := qb.pins[keys[0]]; ok {
+		t.Errorf("Expected the first key to be dropped")
+	}
+}
+
+func TestNextDay(t *testing.T) {
+	loc := time.FixedZone("UTC-5", -5*60*60)
+	tests := []struct {
+		t, want time.Time
+	}{
+		{time.Date(2025, 3, 14, 10, 0, 0, 0, loc), time.Date(2025, 3, 15, 0, 0, 0, 0, loc)},
+		{time.Date(2025, 12, 31, 23, 59, 59, 0, loc), time.Date(2026, 1, 1, 0, 0, 0, 0, loc)},
+		{time.Date(2025, 2, 28, 0, 0, 0, 0, loc), time.Date(2025, 3, 1, 0, 0, 0, 0, loc)},
+	}
+	for _, tt := range tests {
+		if got := NextDay(tt.t); !got.Equal(tt.want) {
+			t.Errorf("NextDay(%v): expected %v, got %v", tt.t, tt.want, got)
+		}
+	}
+}
+
+func TestDailyQuotations(t *testing.T) {
+	qb := New()
+	entries, err := DailyQuotations(qb, time.Now(), 3, time.Now())
+	if err != nil || len(entries) != 0 {
+		t.Errorf("Expected no entries on empty QuoteBook, got %v, %v", entries, err)
+	}
+
+	qb.FillExample()
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	from := time.Date(2025, 3, 30, 15, 0, 0, 0, loc)
+	entries, err = DailyQuotations(qb, from, 3, from)
+	if err != nil {
+		t.Fatalf("DailyQuotations failed: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(entries))
+	}
+	for i, want := range []time.Time{
+		time.Date(2025, 3, 30, 0, 0, 0, 0, loc),
+		time.Date(2025, 3, 31, 0, 0, 0, 0, loc),
+		time.Date(2025, 4, 1, 0, 0, 0, 0, loc),
+	} {
+		if !entries[i].Day.Equal(want) {
+			t.Errorf("Expected day %v, got %v", want, entries[i].Day)
+		}
+		q, _ := DailyQuotation(qb, want)
+		if !reflect.DeepEqual(entries[i].Quote, *q) {
+			t.Errorf("Expected the quote of the day %+v, got %+v", *q, entries[i].Quote)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quote
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// exportBatchSize is the number of quotes read at once from a Store
+// while exporting
+const exportBatchSize = 100
+
+// exporter writes quotes in a Format
+type exporter interface {
+	begin() error
+	write(q *Quotation, first bool) error
+	end(empty bool) error
+}
+
+// Export writes all the quotes in store to w in the given format.
+// Quotes are read and written in batches, so that they are never all
+// held in memory. Batches are read by ID, so that changes in the meantime
+// cannot make the export skip or repeat any.
+func Export(store Store, w io.Writer, format Format) error {
+	var e exporter
+	switch format {
+	case FormatJSON:
+		e = &jsonExporter{w: w}
+	case FormatJSONLines:
+		e = &jsonLinesExporter{enc: json.NewEncoder(w)}
+	case FormatCSV:
+		e = &csvExporter{w: csv.NewWriter(w)}
+	case FormatYAML:
+		e = &yamlExporter{w: w}
+	case FormatMarkdown:
+		e = &markdownExporter{w: w}
+	case FormatFortune:
+		e = &fortuneExporter{w: w}
+	default:
+		return fmt.Errorf("cannot export to %q format", format)
+	}
+
+	if err := e.begin(); err != nil {
+		return err
+	}
+	lastID, written := -1, 0
+	for {
+		quotes, err := store.ListQuotesAfter(lastID, exportBatchSize)
+		if err != nil {
+			return err
+		}
+		for i := range quotes {
+			if err := e.write(&quotes[i], written == 0); err != nil {
+				return err
+			}
+			written++
+		}
+		if len(quotes) < exportBatchSize {
+			break
+		}
+		lastID = quotes[len(quotes)-1].ID
+	}
+	return e.end(written == 0)
+}
+
+type jsonExporter struct {
+	w io.Writer
+}
+
+func (e *jsonExporter) begin() error {
+	_, err := io.WriteString(e.w, "[")
+	return err
+}
+
+func (e *jsonExporter) write(q *Quotation, first bool) error {
+	data, err := json.Marshal(q)
+	if err != nil {
+		return err
+	}
+	sep := ",\n  "
+	if first {
+		sep = "\n  "
+	}
+	_, err = io.WriteString(e.w, sep+string(data))
+	return err
+}
+
+func (e *jsonExporter) end(empty bool) error {
+	if empty {
+		_, err := io.WriteString(e.w, "]\n")
+		return err
+	}
+	_, err := io.WriteString(e.w, "\n]\n")
+	return err
+}
+
+type jsonLinesExporter struct {
+	enc *json.Encoder
+}
+
+func (e *jsonLinesExporter) begin() error { return nil }
+
+func (e *jsonLinesExporter) write(q *Quotation, _ bool) error {
+	return e.enc.Encode(q)
+}
+
+func (e *jsonLinesExporter) end(bool) error { return nil }
+
+type csvExporter struct {
+	w *csv.Writer
+}
+
+func (e *csvExporter) begin() error {
+	return e.w.Write([]string{"quote", "author", "tags"})
+}
+
+func (e *csvExporter) write(q *Quotation, _ bool) error {
+	return e.w.Write([]string{q.Quote, q.Author, strings.Join(q.Tags, ",")})
+}
+
+func (e *csvExporter) end(bool) error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+type yamlExporter struct {
+	w io.Writer
+}
+
+func (e *yamlExporter) begin() error { return nil }
+
+func (e *yamlExporter) write(q *Quotation, _ bool) error {
+	// A sequence with a single element is encoded as a "- " item,
+	// which can be appended to the previous ones
+	data, err := yaml.Marshal([]*Quotation{q})
+	if err != nil {
+		return err
+	}
+	_, err = e.w.Write(data)
+	return err
+}
+
+func (e *yamlExporter) end(empty bool) error {
+	if empty {
+		_, err := io.WriteString(e.w, "[]\n")
+		return err
+	}
+	return nil
+}
+
+type markdownExporter struct {
+	w io.Writer
+}
+
+func (e *markdownExporter) begin() error {
+	_, err := io.WriteString(e.w, "# Quotes\n")
+	return err
+}
+
+func (e *markdownExporter) write(q *Quotation, _ bool) error {
+	if _, err := io.WriteString(e.w, "\n"); err != nil {
+		return err
+	}
+	return q.WriteMarkdown(e.w)
+}
+
+func (e *markdownExporter) end(bool) error { return nil }
+
+type fortuneExporter struct {
+	w io.Writer
+}
+
+func (e *fortuneExporter) begin() error { return nil }
+
+func (e *fortuneExporter) write(q *Quotation, first bool) error {
+	var b strings.Builder
+	if !first {
+		b.WriteString("%\n")
+	}
+	for _, line := range strings.Split(q.Quote, "\n") {
+		if fortuneSpecial(line) {
+			line = `\` + line
+		}
+		b.WriteString(line + "\n")
+	}
+	if q.Author != "" {
+		b.WriteString("\t\t-- " + q.Author + "\n")
+	}
+	_, err := io.WriteString(e.w, b.String())
+	return err
+}
+
+func (e *fortuneExporter) end(bool) error { return nil }
@@ -0,0 +1,169 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quote
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExport_RoundTrip(t *testing.T) {
+	qb := New()
+	qb.SetMaxQuotes(1000)
+	qb.FillExample()
+	for i := 0; i < 2*exportBatchSize; i++ {
+		_, _ = qb.AddQuote(Quotation{Quote: fmt.Sprintf("Quote, \"number\" %d\nsecond line", i), Author: "Tester"})
+	}
+	// Lines which would be read as separators or attributions of fortunes
+	for _, q := range []string{"Before\n%\nafter", "Not an author:\n\t\t-- Tester", "-- Dashes\n\\%\n\\\\-- backslashes"} {
+		if _, err := qb.AddQuote(Quotation{Quote: q, Tags: []string{"special", "tags"}}); err != nil {
+			t.Fatalf("AddQuote failed: %v", err)
+		}
+	}
+	want, _ := qb.ListQuotes(0, 0)
+	for i := range want {
+		want[i].ID = 0
+	}
+
+	for _, format := range ImportFormats {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Export(qb, &buf, format); err != nil {
+				t.Fatalf("Export failed: %v", err)
+			}
+
+			imported := New()
+			imported.SetMaxQuotes(1000)
+			report, err := Import(imported, &buf, format, ImportOptions{})
+			if err != nil {
+				t.Fatalf("Import failed: %v", err)
+			}
+			if len(report.Errors) != 0 {
+				t.Fatalf("Unexpected import errors: %+v", report.Errors)
+			}
+			got, _ := imported.ListQuotes(0, 0)
+			for i := range got {
+				got[i].ID = 0
+				// fortune files have no tags
+				if format == FormatFortune {
+					got[i].Tags = want[i].Tags
+				}
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Quotes differ after export and import")
+			}
+		})
+	}
+}
+
+// changingStore is a Store deleting its first quote, which was already
+// exported, whenever a batch of quotes is listed
+type changingStore struct {
+	*QuoteBook
+}
+
+func (s changingStore) ListQuotesAfter(afterID, limit int) ([]Quotation, error) {
+	quotes, err := s.QuoteBook.ListQuotesAfter(afterID, limit)
+	if first, _ := s.QuoteBook.ListQuotes(0, 1); afterID >= 0 && len(first) == 1 {
+		_ = s.DeleteQuote(first[0].ID)
+	}
+	return quotes, err
+}
+
+func TestExport_Consistent(t *testing.T) {
+	qb := New()
+	qb.SetMaxQuotes(1000)
+	n := 2*exportBatchSize + exportBatchSize/2
+	for i := 0; i < n; i++ {
+		_, _ = qb.AddQuote(Quotation{Quote: fmt.Sprintf("Quote %d", i)})
+	}
+	var buf bytes.Buffer
+	if err := Export(changingStore{qb}, &buf, FormatJSONLines); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	imported := New()
+	imported.SetMaxQuotes(1000)
+	if _, err := Import(imported, &buf, FormatJSONLines, ImportOptions{}); err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	quotes, _ := imported.ListQuotes(0, 0)
+	if len(quotes) != n {
+		t.Fatalf("Expected %d quotes exported while deleting some, got %d", n, len(quotes))
+	}
+	for i, q := range quotes {
+		if want := fmt.Sprintf("Quote %d", i); q.Quote != want {
+			t.Fatalf("Expected %q exported at %d, got %q", want, i, q.Quote)
+		}
+	}
+}
+
+func TestExport_Markdown(t *testing.T) {
+	qb := New()
+	_, _ = qb.AddQuote(Quotation{Quote: "Eat the frog first.", Author: "Brian Tracy"})
+	_, _ = qb.AddQuote(Quotation{Quote: "Anonymous"})
+	var buf bytes.Buffer
+	if err := Export(qb, &buf, FormatMarkdown); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	want := "# Quotes\n\n> Eat the frog first.\n>\n> — Brian Tracy\n\n> Anonymous\n"
+	if buf.String() != want {
+		t.Errorf("Expected %q, got %q", want, buf.String())
+	}
+}
+
+func TestExport_MarkdownEscaping(t *testing.T) {
+	qb := New()
+	_, _ = qb.AddQuote(Quotation{Quote: "# Not a *heading*\n- nor <b>a</b> list\n1. item_one", Author: "[Someone]"})
+	var buf bytes.Buffer
+	if err := Export(qb, &buf, FormatMarkdown); err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	want := "# Quotes\n\n> \\# Not a \\*heading\\*\n> \\- nor \\<b\\>a\\</b\\> list\n> 1\\. item\\_one\n>\n> — \\[Someone\\]\n"
+	if buf.String() != want {
+		t.Errorf("Expected %q, got %q", want, buf.String())
+	}
+}
+
+func TestExport_Empty(t *testing.T) {
+	for _, format := range ExportFormats {
+		var buf bytes.Buffer
+		if err := Export(New(), &buf, format); err != nil {
+			t.Errorf("%s: Export failed: %v", format, err)
+		}
+		if format == FormatJSON || format == FormatYAML {
+			if got := strings.TrimSpace(buf.String()); got != "[]" {
+				t.Errorf("%s: expected empty list, got %q", format, got)
+			}
+		}
+	}
+}
+
+func TestExport_Errors(t *testing.T) {
+	qb := New()
+	qb.FillExample()
+	if err := Export(qb, &bytes.Buffer{}, Format("xml")); err == nil {
+		t.Error("Expected error for unknown format, got nil")
+	}
+	for _, format := range ExportFormats {
+		if err := Export(qb, errorWriter{}, format); err == nil {
+			t.Errorf("%s: expected error from errorWriter, got nil", format)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quote
+
+import (
+	"encoding/xml"
+	"io"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// feedTitle is the title of the feeds of quotes of the day
+const feedTitle = "Quotaday: quote of the day"
+
+// Feed is the feed of the quotes of the last days, which can be written
+// in the RSS 2.0 and Atom formats
+type Feed struct {
+	// BaseURL is the URL the service is reachable at, used to link the
+	// quotes and to identify the entries of the feed
+	BaseURL string
+	// Entries are the quotes of the day, most recent first
+	Entries []DailyEntry
+}
+
+// NewFeed returns the Feed of the quotes of the day of the given number
+// of days, up to the calendar day of t in its location
+func NewFeed(store Store, baseURL string, t time.Time, days int) (*Feed, error) {
+	entries, err := DailyQuotations(store, t.AddDate(0, 0, 1-days), days, t)
+	if err != nil {
+		return nil, err
+	}
+	slices.Reverse(entries)
+	return &Feed{BaseURL: baseURL, Entries: entries}, nil
+}
+
+// Updated returns the time the most recent entry was published, the zero
+// time if the Feed is empty
+func (f *Feed) Updated() time.Time {
+	if len(f.Entries) == 0 {
+		return time.Time{}
+	}
+	return f.Entries[0].Day
+}
+
+// entryID returns the identifier of the entry of the given day, which
+// is a tag URI as defined in RFC 4151. It does not depend on the quote of
+// the day, so that it does not change when quotes are edited.
+func (f *Feed) entryID(day time.Time) string {
+	return "tag:" + hostname(f.BaseURL) + "," + day.Format(time.DateOnly) + ":daily"
+}
+
+// hostname returns the host name in baseURL, used to identify the quotes
+// of the day, or a fixed name if there is none
+func hostname(baseURL string) string {
+	if u, err := url.Parse(baseURL); err == nil && u.Hostname() != "" {
+		return u.Hostname()
+	}
+	return "quotaday"
+}
+
+// entryTitle returns the title of the entry of the given day
+func entryTitle(day time.Time) string {
+	return "Quote of the day for " + day.Format("Monday, 2 January 2006")
+}
+
+// entryText returns the quote of an entry with its author
+func entryText(q *Quotation) string {
+	text := "“" + q.Quote + "”"
+	if q.Author != "" {
+		text += " — " + q.Author
+	}
+	return text
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	Description string  `xml:"description"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// WriteRSS writes f in the RSS 2.0 format
+func (f *Feed) WriteRSS(w io.Writer) error {
+	feed := rssFeed{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:       feedTitle,
+			Link:        f.BaseURL + "/quote/today",
+			Description: "A quote for every day",
+		},
+	}
+	if len(f.Entries) > 0 {
+		feed.Channel.LastBuildDate = f.Updated().Format(time.RFC1123Z)
+	}
+	for _, e := range f.Entries {
+		feed.Channel.Items = append(feed.Channel.Items, rssItem{
+			Title:       entryTitle(e.Day),
+			Link:        f.BaseURL + "/quotes/" + strconv.Itoa(e.Quote.ID),
+			Description: entryText(&e.Quote),
+			GUID:        rssGUID{Value: f.entryID(e.Day)},
+			PubDate:     e.Day.Format(time.RFC1123Z),
+		})
+	}
+	return writeXML(w, feed)
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Links   []atomLink  `xml:"link"`
+	Updated string      `xml:"updated"`
+	Author  atomPerson  `xml:"author"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	Title     string      `xml:"title"`
+	ID        string      `xml:"id"`
+	Link      atomLink    `xml:"link"`
+	Published string      `xml:"published"`
+	Updated   string      `xml:"updated"`
+	Author    *atomPerson `xml:"author,omitempty"`
+	Content   atomContent `xml:"content"`
+}
+
+type atomContent struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+// WriteAtom writes f in the Atom format, as defined in RFC 4287
+func (f *Feed) WriteAtom(w io.Writer) error {
+	updated := f.Updated()
+	if updated.IsZero() {
+		// Atom feeds must always have an update time
+		updated = time.Unix(0, 0).UTC()
+	}
+	feed := atomFeed{
+		Title: feedTitle,
+		ID:    f.BaseURL + "/feed.atom",
+		Links: []atomLink{
+			{Href: f.BaseURL + "/feed.atom", Rel: "self"},
+			{Href: f.BaseURL + "/quote/today"},
+		},
+		Updated: updated.Format(time.RFC3339),
+		Author:  atomPerson{Name: "Quotaday"},
+	}
+	for _, e := range f.Entries {
+		entry := atomEntry{
+			Title:     entryTitle(e.Day),
+			ID:        f.entryID(e.Day),
+			Link:      atomLink{Href: f.BaseURL + "/quotes/" + strconv.Itoa(e.Quote.ID)},
+			Published: e.Day.Format(time.RFC3339),
+			Updated:   e.Day.Format(time.RFC3339),
+			Content:   atomContent{Type: "text", Value: entryText(&e.Quote)},
+		}
+		if e.Quote.Author != "" {
+			entry.Author = &atomPerson{Name: e.Quote.Author}
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+	return writeXML(w, feed)
+}
+
+// writeXML writes v as an indented XML document
+func writeXML(w io.Writer, v any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,121 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quote
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewFeed(t *testing.T) {
+	qb := New()
+	qb.FillExample()
+	now := time.Date(2025, 3, 15, 10, 0, 0, 0, time.UTC)
+	f, err := NewFeed(qb, "https://quote.example.com", now, 3)
+	if err != nil {
+		t.Fatalf("NewFeed failed: %v", err)
+	}
+	if len(f.Entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d", len(f.Entries))
+	}
+	want := time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)
+	if !f.Updated().Equal(want) {
+		t.Errorf("Expected the feed to be updated at %v, got %v", want, f.Updated())
+	}
+	if last := f.Entries[2].Day; !last.Equal(want.AddDate(0, 0, -2)) {
+		t.Errorf("Expected the oldest entry two days ago, got %v", last)
+	}
+}
+
+func TestFeedWriteRSS(t *testing.T) {
+	qb := New()
+	qb.FillExample()
+	now := time.Date(2025, 3, 15, 10, 0, 0, 0, time.UTC)
+	f, err := NewFeed(qb, "https://quote.example.com:8443", now, 2)
+	if err != nil {
+		t.Fatalf("NewFeed failed: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := f.WriteRSS(&buf); err != nil {
+		t.Fatalf("WriteRSS failed: %v", err)
+	}
+
+	var got rssFeed
+	if err := xml.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("Invalid RSS: %v\n%s", err, buf.String())
+	}
+	if len(got.Channel.Items) != 2 {
+		t.Fatalf("Expected 2 items, got %d", len(got.Channel.Items))
+	}
+	item := got.Channel.Items[0]
+	if item.GUID.Value != "tag:quote.example.com,2025-03-15:daily" || item.GUID.IsPermaLink {
+		t.Errorf("Unexpected GUID %+v", item.GUID)
+	}
+	if item.PubDate != "Sat, 15 Mar 2025 00:00:00 +0000" {
+		t.Errorf("Unexpected pubDate %q", item.PubDate)
+	}
+	q := f.Entries[0].Quote
+	if !strings.Contains(item.Description, q.Quote) || !strings.HasSuffix(item.Link, "/quotes/"+strconv.Itoa(q.ID)) {
+		t.Errorf("Unexpected item %+v for quote %+v", item, q)
+	}
+}
+
+func TestFeedWriteAtom(t *testing.T) {
+	qb := New()
+	qb.FillExample()
+	now := time.Date(2025, 3, 15, 10, 0, 0, 0, time.FixedZone("UTC+1", 60*60))
+	f, err := NewFeed(qb, "https://quote.example.com", now, 2)
+	if err != nil {
+		t.Fatalf("NewFeed failed: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := f.WriteAtom(&buf); err != nil {
+		t.Fatalf("WriteAtom failed: %v", err)
+	}
+
+	var got atomFeed
+	if err := xml.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("Invalid Atom: %v\n%s", err, buf.String())
+	}
+	if got.Updated != "2025-03-15T00:00:00+01:00" {
+		t.Errorf("Unexpected update time %q", got.Updated)
+	}
+	if len(got.Entries) != 2 || got.Entries[1].ID != "tag:quote.example.com,2025-03-14:daily" {
+		t.Errorf("Unexpected entries %+v", got.Entries)
+	}
+}
+
+func TestFeed_Empty(t *testing.T) {
+	f, err := NewFeed(New(), "", time.Now(), 5)
+	if err != nil {
+		t.Fatalf("NewFeed failed: %v", err)
+	}
+	if len(f.Entries) != 0 || !f.Updated().IsZero() {
+		t.Errorf("Expected an empty feed, got %+v", f)
+	}
+	var buf bytes.Buffer
+	if err := f.WriteAtom(&buf); err != nil {
+		t.Fatalf("WriteAtom failed: %v", err)
+	}
+	if err := f.WriteRSS(errorWriter{}); err == nil {
+		t.Error("Expected error from WriteRSS with errorWriter, got nil")
+	}
+}
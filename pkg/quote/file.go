@@ -0,0 +1,200 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quote
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// fileData is the on-disk format of a QuoteBook
+type fileData struct {
+	NextID int         `json:"next_id"`
+	Quotes []Quotation `json:"quotes"`
+	// Pins are the IDs of the quotes pinned to keys, e.g., calendar days
+	Pins map[string]int `json:"pins,omitempty"`
+}
+
+// FileStore is a Store keeping Quotations in memory and persisting every
+// change to a JSON file
+type FileStore struct {
+	*QuoteBook
+	path string
+	// closed is set by Close, with the lock held
+	closed bool
+}
+
+// NewFileStore returns a FileStore backed by the file at path, loading the
+// quotes stored there. If the file does not exist it is created with the
+// example quotes.
+func NewFileStore(path string) (*FileStore, error) {
+	return newFileStore(path, true)
+}
+
+// OpenFileStore returns a FileStore backed by the existing file at path,
+// loading the quotes stored there. If the file does not exist the error
+// wraps fs.ErrNotExist.
+func OpenFileStore(path string) (*FileStore, error) {
+	return newFileStore(path, false)
+}
+
+// newFileStore returns a FileStore backed by the file at path, which is
+// created with the example quotes if missing and create is set
+func newFileStore(path string, create bool) (*FileStore, error) {
+	q := New()
+
+	data, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist) && create:
+		q.FillExample()
+		if err := writeQuotes(path, fileData{NextID: q.nextID, Quotes: q.quoteList}); err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, err
+	default:
+		var fd fileData
+		if err := json.Unmarshal(data, &fd); err != nil {
+			return nil, fmt.Errorf("cannot parse %s: %w", path, err)
+		}
+		if err := fd.fixIDs(); err != nil {
+			return nil, fmt.Errorf("invalid data in %s: %w", path, err)
+		}
+		q.quoteList = fd.Quotes
+		q.nextID = fd.NextID
+		q.pins = fd.Pins
+	}
+
+	q.persist = func(fd fileData) error {
+		return writeQuotes(path, fd)
+	}
+	return &FileStore{QuoteBook: q, path: path}, nil
+}
+
+// Path returns the path of the file backing the FileStore
+func (f *FileStore) Path() string {
+	return f.path
+}
+
+// Close flushes the quotes to the file, once the changes in progress are
+// done, and makes the FileStore read-only: later changes fail with
+// ErrClosed
+func (f *FileStore) Close() error {
+	f.Lock()
+	defer f.Unlock()
+	err := writeQuotes(f.path, fileData{NextID: f.nextID, Quotes: f.quoteList, Pins: f.pins})
+	f.persist = func(fileData) error {
+		return ErrClosed
+	}
+	f.closed = true
+	return err
+}
+
+// CheckHealth returns an error if the FileStore is closed or its file
+// cannot be replaced, e.g., because its directory is read-only or full
+func (f *FileStore) CheckHealth() error {
+	f.Lock()
+	closed := f.closed
+	f.Unlock()
+	if closed {
+		return ErrClosed
+	}
+
+	// Quotes are written to a temporary file in the same directory first
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), "."+filepath.Base(f.path)+".health*")
+	if err != nil {
+		return fmt.Errorf("cannot store quotes: %w", err)
+	}
+	tmp.Close()
+	return os.Remove(tmp.Name())
+}
+
+// fixIDs sorts the quotes by ID and makes sure NextID is greater than
+// any of them. Files written before quotes had IDs get them assigned
+// according to the quote position.
+func (fd *fileData) fixIDs() error {
+	if fd.NextID == 0 {
+		for i := range fd.Quotes {
+			fd.Quotes[i].ID = i
+		}
+	}
+	slices.SortFunc(fd.Quotes, func(a, b Quotation) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	for i, quote := range fd.Quotes {
+		if quote.ID < 0 {
+			return fmt.Errorf("negative id %d", quote.ID)
+		}
+		if i > 0 && fd.Quotes[i-1].ID == quote.ID {
+			return fmt.Errorf("duplicate id %d", quote.ID)
+		}
+		fd.NextID = max(fd.NextID, quote.ID+1)
+	}
+	return nil
+}
+
+// writeQuotes atomically replaces the content of the file at path with
+// fd: data is written to a temporary file in the same directory
+// which is then renamed over the original one, so that a crash can never
+// leave a partially written file behind.
+func writeQuotes(path string, fd fileData) error {
+	data, err := json.MarshalIndent(fd, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	// No-op once the file has been renamed
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+
+	// Make the rename durable too: not all platforms allow to sync a
+	// directory, so this is best effort
+	if d, err := os.Open(dir); err == nil {
+		_ = d.Sync()
+		d.Close()
+	}
+	return nil
+}
@@ -0,0 +1,245 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quote
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewFileStore_NewFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quotes.json")
+	qb, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("NewFileStore failed: %v", err)
+	}
+	if len(qb.quoteList) == 0 {
+		t.Error("Expected example quotes in a new QuoteBook")
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Expected data file to be created: %v", err)
+	}
+}
+
+func TestOpenFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quotes.json")
+	if _, err := OpenFileStore(path); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected fs.ErrNotExist, got %v", err)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected no data file to be created, got %v", err)
+	}
+
+	created, _ := NewFileStore(path)
+	opened, err := OpenFileStore(path)
+	if err != nil {
+		t.Fatalf("OpenFileStore failed: %v", err)
+	}
+	if opened.Count() != created.Count() {
+		t.Errorf("Expected %d quotes, got %d", created.Count(), opened.Count())
+	}
+}
+
+func TestNewFileStore_Persistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quotes.json")
+	qb, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("NewFileStore failed: %v", err)
+	}
+	q := Quotation{Quote: "Persisted", Author: "Tester"}
+	added, err := qb.AddQuote(q)
+	if err != nil {
+		t.Fatalf("AddQuote failed: %v", err)
+	}
+
+	reloaded, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if len(reloaded.quoteList) != len(qb.quoteList) {
+		t.Fatalf("Expected %d quotes after reload, got %d", len(qb.quoteList), len(reloaded.quoteList))
+	}
+	got, err := reloaded.GetQuote(added.ID)
+	if err != nil {
+		t.Fatalf("GetQuote failed: %v", err)
+	}
+	if !reflect.DeepEqual(*got, *added) {
+		t.Errorf("Expected %+v, got %+v", *added, *got)
+	}
+	if reloaded.nextID != qb.nextID {
+		t.Errorf("Expected next id %d after reload, got %d", qb.nextID, reloaded.nextID)
+	}
+
+	// No temporary files should be left around
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("Expected only the data file, found %d entries", len(entries))
+	}
+}
+
+func TestFileStore_Close(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quotes.json")
+	qb, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("NewFileStore failed: %v", err)
+	}
+	if _, err := qb.AddQuote(Quotation{Quote: "Flushed"}); err != nil {
+		t.Fatalf("AddQuote failed: %v", err)
+	}
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if err := qb.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	reloaded, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if reloaded.Count() != qb.Count() {
+		t.Errorf("Expected %d quotes flushed, got %d", qb.Count(), reloaded.Count())
+	}
+
+	if err := qb.CheckHealth(); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed checking the health after Close, got %v", err)
+	}
+	if _, err := qb.AddQuote(Quotation{Quote: "Too late"}); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed adding quotes after Close, got %v", err)
+	}
+	if _, err := qb.GetQuote(0); err != nil {
+		t.Errorf("Expected quotes readable after Close, got %v", err)
+	}
+}
+
+func TestFileStore_Pins(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quotes.json")
+	qb, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("NewFileStore failed: %v", err)
+	}
+	day := time.Date(2025, 3, 14, 10, 0, 0, 0, time.UTC)
+	want, err := DailyQuotation(qb, day)
+	if err != nil {
+		t.Fatalf("DailyQuotation failed: %v", err)
+	}
+
+	reloaded, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("Reload failed: %v", err)
+	}
+	if _, err := reloaded.AddQuote(Quotation{Quote: "Added after a restart"}); err != nil {
+		t.Fatalf("AddQuote failed: %v", err)
+	}
+	if got, err := DailyQuotation(reloaded, day); err != nil || got.ID != want.ID {
+		t.Errorf("Expected the pinned quote %d after a restart, got %+v, %v", want.ID, got, err)
+	}
+}
+
+func TestFileStore_CheckHealth(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "data")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	qb, err := NewFileStore(filepath.Join(dir, "quotes.json"))
+	if err != nil {
+		t.Fatalf("NewFileStore failed: %v", err)
+	}
+	if err := qb.CheckHealth(); err != nil {
+		t.Errorf("Expected a healthy FileStore, got %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("Expected only the data file, found %d entries", len(entries))
+	}
+
+	// The directory of the file is gone
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := qb.CheckHealth(); err == nil {
+		t.Error("Expected an error when the file cannot be written")
+	}
+}
+
+func TestNewFileStore_CorruptedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quotes.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFileStore(path); err == nil {
+		t.Error("Expected error loading a corrupted file, got nil")
+	}
+}
+
+func TestAddQuote_PersistFailure(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "quotes.json")
+	qb, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("NewFileStore failed: %v", err)
+	}
+	before := len(qb.quoteList)
+
+	// Make the temporary file creation fail
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := qb.AddQuote(Quotation{Quote: "Lost", Author: "Tester"}); err == nil {
+		t.Error("Expected error when the data file cannot be written, got nil")
+	}
+	if len(qb.quoteList) != before {
+		t.Errorf("Expected %d quotes after failed add, got %d", before, len(qb.quoteList))
+	}
+}
+
+func TestNewFileStore_LegacyFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quotes.json")
+	legacy := `{"quotes": [{"Quote": "First", "Author": "A"}, {"Quote": "Second", "Author": "B"}]}`
+	if err := os.WriteFile(path, []byte(legacy), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	qb, err := NewFileStore(path)
+	if err != nil {
+		t.Fatalf("NewFileStore failed: %v", err)
+	}
+	got, err := qb.GetQuote(1)
+	if err != nil {
+		t.Fatalf("GetQuote failed: %v", err)
+	}
+	if got.Quote != "Second" {
+		t.Errorf("Expected quote at position 1 to get id 1, got %+v", got)
+	}
+	if qb.nextID != 2 {
+		t.Errorf("Expected next id 2, got %d", qb.nextID)
+	}
+}
+
+func TestNewFileStore_DuplicateIDs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quotes.json")
+	data := `{"next_id": 3, "quotes": [{"id": 1, "quote": "A"}, {"id": 1, "quote": "B"}]}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewFileStore(path); err == nil {
+		t.Error("Expected error loading duplicate ids, got nil")
+	}
+}
@@ -0,0 +1,131 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quote
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Format is a file format to import and export quotes
+type Format string
+
+const (
+	// FormatJSON is a JSON array of quotes
+	FormatJSON Format = "json"
+	// FormatJSONLines is a quote encoded in JSON on each line
+	FormatJSONLines Format = "jsonl"
+	// FormatCSV has a header row naming the "quote", "author" and "tags"
+	// columns, tags being separated by commas
+	FormatCSV Format = "csv"
+	// FormatYAML is a YAML sequence of quotes
+	FormatYAML Format = "yaml"
+	// FormatFortune is the format of the fortune program: quotes are
+	// separated by lines containing only "%" and the author is on the last
+	// line, after "--"
+	FormatFortune Format = "fortune"
+	// FormatMarkdown is a Markdown document with a blockquote for each
+	// quote. Quotes can only be exported to this format.
+	FormatMarkdown Format = "markdown"
+)
+
+var (
+	// ImportFormats are the formats quotes can be imported from
+	ImportFormats = []Format{FormatJSON, FormatJSONLines, FormatCSV, FormatYAML, FormatFortune}
+	// ExportFormats are the formats quotes can be exported to
+	ExportFormats = []Format{FormatJSON, FormatJSONLines, FormatCSV, FormatYAML, FormatMarkdown, FormatFortune}
+)
+
+// hasIDs tells whether the quotes in files of format f have IDs
+func (f Format) hasIDs() bool {
+	return f == FormatJSON || f == FormatJSONLines || f == FormatYAML
+}
+
+// ParseFormat returns the Format named name, also accepting
+// the usual file extensions
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(strings.TrimPrefix(name, ".")) {
+	case "json":
+		return FormatJSON, nil
+	case "jsonl", "ndjson":
+		return FormatJSONLines, nil
+	case "csv":
+		return FormatCSV, nil
+	case "yaml", "yml":
+		return FormatYAML, nil
+	case "fortune", "txt":
+		return FormatFortune, nil
+	case "markdown", "md":
+		return FormatMarkdown, nil
+	}
+	return "", fmt.Errorf("unknown format %q", name)
+}
+
+// FormatFromFilename returns the Format matching the extension of filename
+func FormatFromFilename(filename string) (Format, error) {
+	ext := filepath.Ext(filename)
+	if ext == "" {
+		return "", fmt.Errorf("cannot guess the format of %s", filename)
+	}
+	return ParseFormat(ext)
+}
+
+// Extension returns the usual file extension for f, including the dot
+func (f Format) Extension() string {
+	switch f {
+	case FormatMarkdown:
+		return ".md"
+	case FormatFortune:
+		return ""
+	}
+	return "." + string(f)
+}
+
+// MediaType returns the MIME type of f
+func (f Format) MediaType() string {
+	switch f {
+	case FormatJSON:
+		return "application/json"
+	case FormatJSONLines:
+		return "application/jsonl"
+	case FormatCSV:
+		return "text/csv"
+	case FormatYAML:
+		return "application/yaml"
+	case FormatMarkdown:
+		return "text/markdown"
+	}
+	return "text/plain"
+}
+
+// FormatFromMediaType returns the Format matching a MIME type
+func FormatFromMediaType(mediaType string) (Format, error) {
+	switch mediaType {
+	case "application/json":
+		return FormatJSON, nil
+	case "application/jsonl", "application/x-ndjson", "application/x-jsonlines":
+		return FormatJSONLines, nil
+	case "text/csv":
+		return FormatCSV, nil
+	case "application/yaml", "application/x-yaml", "text/yaml":
+		return FormatYAML, nil
+	case "text/plain":
+		return FormatFortune, nil
+	}
+	return "", fmt.Errorf("unsupported media type %q", mediaType)
+}
@@ -0,0 +1,375 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quote
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ImportError reports a quote which could not be imported
+type ImportError struct {
+	// Line is where the quote starts in the imported file
+	Line int    `json:"line"`
+	Err  string `json:"error"`
+}
+
+func (e ImportError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Err)
+}
+
+// ImportReport is the outcome of an import
+type ImportReport struct {
+	// Imported is the number of quotes imported, or which would have been
+	// imported in a dry run
+	Imported int           `json:"imported"`
+	Errors   []ImportError `json:"errors"`
+	DryRun   bool          `json:"dry_run"`
+}
+
+// ImportOptions changes how quotes are imported
+type ImportOptions struct {
+	// DryRun only checks the quotes, without adding them
+	DryRun bool
+	// KeepIDs adds the quotes with their IDs, e.g., to restore a backup,
+	// instead of assigning them new ones. Quotes with an ID in use are
+	// invalid.
+	KeepIDs bool
+}
+
+// entry is a quote read from an imported file, or the error found
+// reading it
+type entry struct {
+	line  int
+	quote Quotation
+	err   error
+}
+
+// Import adds to store the quotes read from r in the given format.
+// Every quote is validated and the invalid ones are skipped and reported,
+// while the valid ones are added all together. In a dry run quotes are
+// only checked, including whether the store can hold them. An error is
+// returned only if r cannot be parsed at all or the store cannot hold the
+// valid quotes.
+func Import(store Store, r io.Reader, format Format, opts ImportOptions) (*ImportReport, error) {
+	if opts.KeepIDs && !format.hasIDs() {
+		return nil, fmt.Errorf("cannot keep the IDs of quotes in %q format, which has none", format)
+	}
+	entries, err := decode(r, format)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ImportReport{Errors: []ImportError{}, DryRun: opts.DryRun}
+	var quotes []Quotation
+	ids := map[int]bool{}
+	for _, e := range entries {
+		if !opts.KeepIDs {
+			// IDs are assigned by the Store
+			e.quote.ID = 0
+		}
+		if e.err == nil {
+			e.err = e.quote.Validate()
+		}
+		if e.err == nil && opts.KeepIDs {
+			e.err = checkImportedID(store, e.quote.ID, ids)
+		}
+		if e.err != nil {
+			report.Errors = append(report.Errors, ImportError{Line: e.line, Err: e.err.Error()})
+			continue
+		}
+		quotes = append(quotes, e.quote)
+	}
+
+	switch {
+	case len(quotes) == 0:
+	case opts.DryRun:
+		if err := store.CheckQuotes(quotes, opts.KeepIDs); err != nil {
+			return nil, err
+		}
+	case opts.KeepIDs:
+		if _, err := store.RestoreQuotes(quotes); err != nil {
+			return nil, err
+		}
+	default:
+		if _, err := store.AddQuotes(quotes); err != nil {
+			return nil, err
+		}
+	}
+	report.Imported = len(quotes)
+	return report, nil
+}
+
+// decode reads all the quotes in r
+func decode(r io.Reader, format Format) ([]entry, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []entry
+	switch format {
+	case FormatJSON:
+		entries, err = decodeJSON(data)
+	case FormatJSONLines:
+		entries, err = decodeJSONLines(data)
+	case FormatCSV:
+		entries, err = decodeCSV(data)
+	case FormatYAML:
+		entries, err = decodeYAML(data)
+	case FormatFortune:
+		entries, err = decodeFortune(data)
+	default:
+		return nil, fmt.Errorf("cannot import %q format", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", format, err)
+	}
+	return entries, nil
+}
+
+// checkImportedID returns an error if a quote cannot be imported keeping
+// id, because it is negative, in store or among the IDs imported before
+func checkImportedID(store Store, id int, imported map[int]bool) error {
+	switch {
+	case id < 0:
+		return fmt.Errorf("negative id %d", id)
+	case imported[id]:
+		return fmt.Errorf("duplicate id %d", id)
+	}
+	imported[id] = true
+	if _, err := store.GetQuote(id); err == nil {
+		return fmt.Errorf("id %d already in use", id)
+	}
+	return nil
+}
+
+// lineAt returns the line number of offset in data
+func lineAt(data []byte, offset int64) int {
+	offset = min(offset, int64(len(data)))
+	return 1 + bytes.Count(data[:offset], []byte("\n"))
+}
+
+func decodeJSON(data []byte) ([]entry, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	jsonError := func(err error) error {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return fmt.Errorf("line %d: %w", lineAt(data, syntaxErr.Offset), err)
+		}
+		return fmt.Errorf("line %d: %w", lineAt(data, dec.InputOffset()), err)
+	}
+
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, jsonError(err)
+	}
+	if tok != json.Delim('[') {
+		return nil, fmt.Errorf("expected an array of quotes")
+	}
+
+	var entries []entry
+	for dec.More() {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, jsonError(err)
+		}
+		e := entry{line: lineAt(data, dec.InputOffset()-int64(len(raw)))}
+		e.err = json.Unmarshal(raw, &e.quote)
+		entries = append(entries, e)
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, jsonError(err)
+	}
+	return entries, nil
+}
+
+func decodeJSONLines(data []byte) ([]entry, error) {
+	var entries []entry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		e := entry{line: line}
+		e.err = json.Unmarshal(text, &e.quote)
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
+
+func decodeCSV(data []byte) ([]entry, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	header, err := r.Read()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read header: %w", err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["quote"]; !ok {
+		return nil, fmt.Errorf("missing \"quote\" column in header")
+	}
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return record[i]
+		}
+		return ""
+	}
+
+	var entries []entry
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			entries = append(entries, entry{line: parseErr.StartLine, err: parseErr.Err})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := r.FieldPos(0)
+		e := entry{line: line}
+		e.quote.Quote = field(record, "quote")
+		e.quote.Author = field(record, "author")
+		if tags := field(record, "tags"); tags != "" {
+			e.quote.Tags = strings.Split(tags, ",")
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+func decodeYAML(data []byte) ([]entry, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Kind == 0 {
+		// Empty file
+		return nil, nil
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) != 1 || doc.Content[0].Kind != yaml.SequenceNode {
+		return nil, fmt.Errorf("expected a sequence of quotes")
+	}
+
+	var entries []entry
+	for _, node := range doc.Content[0].Content {
+		e := entry{line: node.Line}
+		e.err = node.Decode(&e.quote)
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// fortuneAttribution are the prefixes of the line with the author
+// of a fortune
+var fortuneAttribution = []string{"--", "—", "―"}
+
+// fortuneSpecial tells whether line would be read as a separator of
+// fortunes or as an attribution, or looks escaped: such lines of quotes
+// are escaped with a leading backslash
+func fortuneSpecial(line string) bool {
+	if strings.TrimRight(line, " \t\r") == "%" {
+		return true
+	}
+	trimmed := strings.TrimSpace(line)
+	for _, prefix := range fortuneAttribution {
+		if strings.HasPrefix(trimmed, prefix) {
+			return true
+		}
+	}
+	rest, ok := strings.CutPrefix(line, `\`)
+	return ok && fortuneSpecial(rest)
+}
+
+// unescapeFortune removes the backslash escaping a line of a quote
+func unescapeFortune(line string) string {
+	if rest, ok := strings.CutPrefix(line, `\`); ok && fortuneSpecial(rest) {
+		return rest
+	}
+	return line
+}
+
+func decodeFortune(data []byte) ([]entry, error) {
+	var entries []entry
+	var lines []string
+	start := 0
+
+	flush := func() {
+		for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+			lines = lines[:len(lines)-1]
+		}
+		if len(lines) == 0 {
+			return
+		}
+
+		e := entry{line: start}
+		last := strings.TrimSpace(lines[len(lines)-1])
+		for _, prefix := range fortuneAttribution {
+			if author, ok := strings.CutPrefix(last, prefix); ok {
+				e.quote.Author = strings.TrimSpace(author)
+				lines = lines[:len(lines)-1]
+				break
+			}
+		}
+		for i := range lines {
+			lines[i] = unescapeFortune(lines[i])
+		}
+		e.quote.Quote = strings.TrimSpace(strings.Join(lines, "\n"))
+		entries = append(entries, e)
+		lines = nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimRight(scanner.Text(), " \t\r")
+		if text == "%" {
+			flush()
+			continue
+		}
+		if len(lines) == 0 && text == "" {
+			// Skip blank lines before the quote
+			continue
+		}
+		if len(lines) == 0 {
+			start = line
+		}
+		lines = append(lines, text)
+	}
+	flush()
+	return entries, scanner.Err()
+}
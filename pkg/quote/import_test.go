@@ -0,0 +1,234 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quote
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestImport_Formats(t *testing.T) {
+	want := []Quotation{
+		{Quote: "Eat the frog first.", Author: "Brian Tracy", Tags: []string{"productivity"}},
+		{Quote: "Imperfect action beats perfect inaction.", Author: "Harry S. Truman"},
+	}
+	tests := []struct {
+		format Format
+		data   string
+	}{
+		{FormatJSON, `[
+  {"quote": "Eat the frog first.", "author": "Brian Tracy", "tags": ["productivity"]},
+  {"quote": "Imperfect action beats perfect inaction.", "author": "Harry S. Truman"}
+]`},
+		{FormatJSONLines, `{"quote": "Eat the frog first.", "author": "Brian Tracy", "tags": ["productivity"]}
+
+{"quote": "Imperfect action beats perfect inaction.", "author": "Harry S. Truman"}
+`},
+		{FormatCSV, `Quote,Author,Tags
+"Eat the frog first.",Brian Tracy,productivity
+Imperfect action beats perfect inaction.,Harry S. Truman,
+`},
+		{FormatYAML, `- quote: Eat the frog first.
+  author: Brian Tracy
+  tags: [productivity]
+- quote: Imperfect action beats perfect inaction.
+  author: Harry S. Truman
+`},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			qb := New()
+			report, err := Import(qb, strings.NewReader(tt.data), tt.format, ImportOptions{})
+			if err != nil {
+				t.Fatalf("Import failed: %v", err)
+			}
+			if report.Imported != 2 || len(report.Errors) != 0 {
+				t.Errorf("Unexpected report %+v", report)
+			}
+			got, _ := qb.ListQuotes(0, 0)
+			for i := range got {
+				got[i].ID = 0
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Expected %+v, got %+v", want, got)
+			}
+		})
+	}
+}
+
+func TestImport_Fortune(t *testing.T) {
+	data := `
+Eat the frog first.
+		-- Brian Tracy
+%
+Anonymous quote
+spanning two lines
+%
+Succeed or survive
+(but try).
+— Mel Robbins
+%
+`
+	qb := New()
+	report, err := Import(qb, strings.NewReader(data), FormatFortune, ImportOptions{})
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if report.Imported != 3 {
+		t.Fatalf("Expected 3 quotes imported, got %+v", report)
+	}
+	got, _ := qb.ListQuotes(0, 0)
+	want := []Quotation{
+		{ID: 0, Quote: "Eat the frog first.", Author: "Brian Tracy"},
+		{ID: 1, Quote: "Anonymous quote\nspanning two lines"},
+		{ID: 2, Quote: "Succeed or survive\n(but try).", Author: "Mel Robbins"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+}
+
+func TestImport_InvalidRecords(t *testing.T) {
+	tests := []struct {
+		format Format
+		data   string
+		lines  []int
+	}{
+		{FormatJSON, "[\n  {\"quote\": \"Valid\"},\n  {\"quote\": \"\"},\n  {\"quote\": 42}\n]", []int{3, 4}},
+		{FormatJSONLines, "{\"quote\": \"Valid\"}\nnot json\n{\"author\": \"Nobody\"}\n", []int{2, 3}},
+		{FormatCSV, "quote,author\nValid,A\n,B\n\"bad\"quote\",C\n", []int{3, 4}},
+		{FormatYAML, "- quote: Valid\n- author: Nobody\n- [not, a, quote]\n", []int{2, 3}},
+		{FormatFortune, "Valid\n%\n-- Nobody\n%\n", []int{3}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			qb := New()
+			report, err := Import(qb, strings.NewReader(tt.data), tt.format, ImportOptions{})
+			if err != nil {
+				t.Fatalf("Import failed: %v", err)
+			}
+			if report.Imported != 1 || qb.Count() != 1 {
+				t.Errorf("Expected the valid quote to be imported, got %+v", report)
+			}
+			var lines []int
+			for _, e := range report.Errors {
+				lines = append(lines, e.Line)
+			}
+			if !reflect.DeepEqual(lines, tt.lines) {
+				t.Errorf("Expected errors at lines %v, got %+v", tt.lines, report.Errors)
+			}
+		})
+	}
+}
+
+func TestImport_DryRun(t *testing.T) {
+	qb := New()
+	report, err := Import(qb, strings.NewReader(`[{"quote": "A"}, {"quote": "B"}]`), FormatJSON, ImportOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if !report.DryRun || report.Imported != 2 {
+		t.Errorf("Unexpected report %+v", report)
+	}
+	if qb.Count() != 0 {
+		t.Errorf("Expected no quotes to be added in a dry run, got %d", qb.Count())
+	}
+
+	// A dry run fails as the import would
+	qb.SetMaxQuotes(1)
+	_, err = Import(qb, strings.NewReader(`[{"quote": "A"}, {"quote": "B"}]`), FormatJSON, ImportOptions{DryRun: true})
+	if !errors.Is(err, ErrFull) {
+		t.Errorf("Expected ErrFull in a dry run, got %v", err)
+	}
+}
+
+func TestImport_KeepIDs(t *testing.T) {
+	qb := New()
+	qb.FillExample()
+	_ = qb.DeleteQuote(2)
+	data := "[\n  {\"id\": 2, \"quote\": \"A\"},\n  {\"id\": 8, \"quote\": \"B\"},\n  {\"id\": 8, \"quote\": \"Duplicate\"},\n  {\"id\": 1, \"quote\": \"In use\"}\n]"
+	report, err := Import(qb, strings.NewReader(data), FormatJSON, ImportOptions{KeepIDs: true})
+	if err != nil {
+		t.Fatalf("Import failed: %v", err)
+	}
+	if report.Imported != 2 || len(report.Errors) != 2 || report.Errors[0].Line != 4 || report.Errors[1].Line != 5 {
+		t.Errorf("Unexpected report %+v", report)
+	}
+	for id, want := range map[int]string{2: "A", 8: "B"} {
+		if q, err := qb.GetQuote(id); err != nil || q.Quote != want {
+			t.Errorf("Expected %q imported with ID %d, got %+v, %v", want, id, q, err)
+		}
+	}
+
+	// Only some formats have IDs
+	if _, err := Import(qb, strings.NewReader("quote\nC\n"), FormatCSV, ImportOptions{KeepIDs: true}); err == nil {
+		t.Error("Expected error keeping the IDs of CSV quotes, got nil")
+	}
+}
+
+func TestImport_Errors(t *testing.T) {
+	tests := []struct {
+		format Format
+		data   string
+	}{
+		{FormatJSON, `{"quote": "not an array"}`},
+		{FormatJSON, "[\n{\"quote\": \"A\"},\n{\"quote\": "},
+		{FormatCSV, "author\nNobody\n"},
+		{FormatYAML, "quote: not a sequence\n"},
+		{Format("xml"), "<quote/>"},
+	}
+	for _, tt := range tests {
+		if _, err := Import(New(), strings.NewReader(tt.data), tt.format, ImportOptions{}); err == nil {
+			t.Errorf("%s %q: expected error, got nil", tt.format, tt.data)
+		}
+	}
+
+	qb := New()
+	qb.SetMaxQuotes(1)
+	_, err := Import(qb, strings.NewReader(`[{"quote": "A"}, {"quote": "B"}, {"quote": "C"}]`), FormatJSON, ImportOptions{})
+	if !errors.Is(err, ErrFull) {
+		t.Errorf("Expected ErrFull, got %v", err)
+	}
+	if qb.Count() != 0 {
+		t.Errorf("Expected no quotes to be added, got %d", qb.Count())
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	tests := map[string]Format{
+		"json":    FormatJSON,
+		".JSONL":  FormatJSONLines,
+		"ndjson":  FormatJSONLines,
+		"csv":     FormatCSV,
+		"yml":     FormatYAML,
+		"fortune": FormatFortune,
+	}
+	for name, want := range tests {
+		got, err := ParseFormat(name)
+		if err != nil || got != want {
+			t.Errorf("ParseFormat(%q): expected %q, got %q (%v)", name, want, got, err)
+		}
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("Expected error for unknown format, got nil")
+	}
+	if got, err := FormatFromFilename("/tmp/quotes.yaml"); err != nil || got != FormatYAML {
+		t.Errorf("FormatFromFilename: expected yaml, got %q (%v)", got, err)
+	}
+}
@@ -0,0 +1,241 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quote
+
+import (
+	"cmp"
+	"fmt"
+	"html"
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// maxSnippetWords is the number of words of a quote kept in a search
+// result snippet around the first match
+const maxSnippetWords = 24
+
+// SearchResult is a Quotation matching a search query
+type SearchResult struct {
+	Quotation
+	Score float64 `json:"score"`
+	// Snippet is the HTML escaped quote, shortened around the first match,
+	// with the matching words wrapped in <mark> tags
+	Snippet string `json:"snippet"`
+	// AuthorSnippet is the HTML escaped author with the matching words
+	// wrapped in <mark> tags
+	AuthorSnippet string `json:"author_snippet"`
+}
+
+// index is an inverted index of the words in the quotes and their authors
+type index struct {
+	// postings maps each term to the IDs of the quotes containing it,
+	// with the number of occurrences
+	postings map[string]map[int]int
+	// terms maps each quote ID to its terms, to remove it from postings
+	terms map[int][]string
+}
+
+func newIndex() *index {
+	return &index{
+		postings: map[string]map[int]int{},
+		terms:    map[int][]string{},
+	}
+}
+
+func (idx *index) add(quote *Quotation) {
+	terms := slices.Concat(tokenize(quote.Quote), tokenize(quote.Author))
+	idx.terms[quote.ID] = terms
+	for _, term := range terms {
+		if idx.postings[term] == nil {
+			idx.postings[term] = map[int]int{}
+		}
+		idx.postings[term][quote.ID]++
+	}
+}
+
+func (idx *index) remove(id int) {
+	for _, term := range idx.terms[id] {
+		delete(idx.postings[term], id)
+		if len(idx.postings[term]) == 0 {
+			delete(idx.postings, term)
+		}
+	}
+	delete(idx.terms, id)
+}
+
+// search returns the IDs of the quotes containing any of the terms in
+// query, mapped to their relevance according to TF-IDF
+func (idx *index) search(query string) map[int]float64 {
+	scores := map[int]float64{}
+	total := float64(len(idx.terms))
+	for _, term := range uniqueTerms(query) {
+		postings := idx.postings[term]
+		if len(postings) == 0 {
+			continue
+		}
+		idf := math.Log(1 + total/float64(len(postings)))
+		for id, tf := range postings {
+			scores[id] += float64(tf) * idf
+		}
+	}
+	return scores
+}
+
+// Search returns up to limit quotes matching query, the most relevant first.
+// Matching ignores case and diacritics; all results are returned if limit
+// is not positive.
+func (q *QuoteBook) Search(query string, limit int) ([]SearchResult, error) {
+	terms := uniqueTerms(query)
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("empty search query")
+	}
+
+	q.Lock()
+	defer q.Unlock()
+
+	var results []SearchResult
+	for id, score := range q.searchIndex().search(query) {
+		pos, err := q.find(id)
+		if err != nil {
+			continue
+		}
+		quote := q.quoteList[pos].clone()
+		results = append(results, SearchResult{
+			Quotation:     quote,
+			Score:         score,
+			Snippet:       highlight(quote.Quote, terms, maxSnippetWords),
+			AuthorSnippet: highlight(quote.Author, terms, 0),
+		})
+	}
+
+	slices.SortFunc(results, func(a, b SearchResult) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.ID, b.ID)
+	})
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// searchIndex returns the search index, building it if the quotes have
+// been replaced altogether. Must be called with the lock held.
+func (q *QuoteBook) searchIndex() *index {
+	if q.index == nil {
+		q.index = newIndex()
+		for i := range q.quoteList {
+			q.index.add(&q.quoteList[i])
+		}
+	}
+	return q.index
+}
+
+// foldDiacritics removes the diacritical marks, e.g., "è" becomes "e"
+var foldDiacritics = transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+
+// normalize returns word lower case and without diacritics
+func normalize(word string) string {
+	folded, _, err := transform.String(foldDiacritics, word)
+	if err != nil {
+		folded = word
+	}
+	return strings.ToLower(folded)
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r)
+}
+
+// tokenize returns the normalized words in text
+func tokenize(text string) []string {
+	var terms []string
+	for _, word := range strings.FieldsFunc(text, func(r rune) bool { return !isWordRune(r) }) {
+		terms = append(terms, normalize(word))
+	}
+	return terms
+}
+
+func uniqueTerms(text string) []string {
+	terms := tokenize(text)
+	slices.Sort(terms)
+	return slices.Compact(terms)
+}
+
+// highlight returns text HTML escaped with the words matching terms wrapped
+// in <mark> tags. If maxWords is positive, text is shortened to maxWords
+// words around the first match.
+func highlight(text string, terms []string, maxWords int) string {
+	type word struct {
+		start, end int
+		match      bool
+	}
+
+	var words []word
+	start := -1
+	for i, r := range text + " " {
+		switch {
+		case isWordRune(r) && start < 0:
+			start = i
+		case !isWordRune(r) && start >= 0:
+			w := word{start: start, end: i}
+			w.match = slices.Contains(terms, normalize(text[start:i]))
+			words = append(words, w)
+			start = -1
+		}
+	}
+	if len(words) == 0 {
+		return html.EscapeString(text)
+	}
+
+	first, last := 0, len(words)-1
+	if maxWords > 0 && len(words) > maxWords {
+		matched := max(slices.IndexFunc(words, func(w word) bool { return w.match }), 0)
+		first = max(matched-maxWords/2, 0)
+		last = min(first+maxWords, len(words)) - 1
+		first = last - maxWords + 1
+	}
+
+	var b strings.Builder
+	pos := 0
+	if first > 0 {
+		b.WriteString("…")
+		pos = words[first].start
+	}
+	for _, w := range words[first : last+1] {
+		b.WriteString(html.EscapeString(text[pos:w.start]))
+		if w.match {
+			b.WriteString("<mark>" + html.EscapeString(text[w.start:w.end]) + "</mark>")
+		} else {
+			b.WriteString(html.EscapeString(text[w.start:w.end]))
+		}
+		pos = w.end
+	}
+	if last < len(words)-1 {
+		b.WriteString("…")
+	} else {
+		b.WriteString(html.EscapeString(text[pos:]))
+	}
+	return b.String()
+}
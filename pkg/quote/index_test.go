@@ -0,0 +1,119 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quote
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSearch(t *testing.T) {
+	qb := New()
+	qb.FillExample()
+
+	results, err := qb.Search("FROG", 0)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != 1 {
+		t.Fatalf("Expected quote 1, got %+v", results)
+	}
+	if results[0].Snippet != "Eat the <mark>frog</mark> first." {
+		t.Errorf("Unexpected snippet %q", results[0].Snippet)
+	}
+
+	// The rarest term weighs more
+	results, err = qb.Search("robbins action", 0)
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	if len(results) != 5 {
+		t.Fatalf("Expected 5 results, got %d", len(results))
+	}
+	if results[0].ID != 2 {
+		t.Errorf("Expected quote 2 to match best, got %+v", results[0])
+	}
+	if results[1].AuthorSnippet != "Mel <mark>Robbins</mark>" {
+		t.Errorf("Unexpected author snippet %q", results[1].AuthorSnippet)
+	}
+
+	results, _ = qb.Search("robbins", 2)
+	if len(results) != 2 {
+		t.Errorf("Expected results to be limited to 2, got %d", len(results))
+	}
+
+	if _, err := qb.Search(" ?! ", 0); err == nil {
+		t.Error("Expected error for an empty query, got nil")
+	}
+}
+
+func TestSearch_Diacritics(t *testing.T) {
+	qb := New()
+	added, _ := qb.AddQuote(Quotation{Quote: "La vérité è sempre rivoluzionaria", Author: "Antonio Gramsci"})
+
+	for _, query := range []string{"verite", "VÉRITÉ", "e"} {
+		results, err := qb.Search(query, 0)
+		if err != nil {
+			t.Fatalf("Search failed: %v", err)
+		}
+		if len(results) != 1 || results[0].ID != added.ID {
+			t.Errorf("%q: expected quote %d, got %+v", query, added.ID, results)
+		}
+	}
+}
+
+func TestSearch_IndexUpdates(t *testing.T) {
+	qb := New()
+	qb.FillExample()
+	if results, _ := qb.Search("frog", 0); len(results) != 1 {
+		t.Fatalf("Expected one result, got %d", len(results))
+	}
+
+	added, _ := qb.AddQuote(Quotation{Quote: "A frog in a well", Author: "Zhuangzi"})
+	if results, _ := qb.Search("frog", 0); len(results) != 2 {
+		t.Errorf("Expected added quote to be found, got %d results", len(results))
+	}
+
+	_ = qb.UpdateQuote(added.ID, Quotation{Quote: "A toad in a well", Author: "Zhuangzi"})
+	if results, _ := qb.Search("frog", 0); len(results) != 1 {
+		t.Errorf("Expected updated quote not to match, got %d results", len(results))
+	}
+	if results, _ := qb.Search("toad", 0); len(results) != 1 {
+		t.Errorf("Expected updated quote to match, got %d results", len(results))
+	}
+
+	_ = qb.DeleteQuote(added.ID)
+	if results, _ := qb.Search("toad", 0); len(results) != 0 {
+		t.Errorf("Expected deleted quote not to match, got %d results", len(results))
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	long := strings.Repeat("word ", 30) + "frog " + strings.Repeat("other ", 30)
+	got := highlight(long, []string{"frog"}, 6)
+	if !strings.HasPrefix(got, "…") || !strings.HasSuffix(got, "…") {
+		t.Errorf("Expected snippet to be shortened on both sides, got %q", got)
+	}
+	if !strings.Contains(got, "<mark>frog</mark>") || strings.Count(got, " ") != 5 {
+		t.Errorf("Expected 6 words around the match, got %q", got)
+	}
+
+	got = highlight(`<b>"Frog"</b>`, []string{"frog"}, 0)
+	if got != "&lt;b&gt;&#34;<mark>Frog</mark>&#34;&lt;/b&gt;" {
+		t.Errorf("Expected text to be escaped, got %q", got)
+	}
+}
@@ -0,0 +1,113 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quote
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Page is a portion of the quotes available in a Store
+type Page struct {
+	Quotes []Quotation `json:"quotes"`
+	// Total is the number of quotes available in the Store
+	Total  int `json:"total"`
+	Offset int `json:"offset"`
+	Limit  int `json:"limit"`
+}
+
+// NewPage returns the Page of up to limit quotes in store starting from offset
+func NewPage(store Store, offset, limit int) (*Page, error) {
+	quotes, err := store.ListQuotes(offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return &Page{
+		Quotes: quotes,
+		Total:  store.Count(),
+		Offset: offset,
+		Limit:  limit,
+	}, nil
+}
+
+// HasPrev tells whether there are quotes before the Page
+func (p *Page) HasPrev() bool {
+	return p.Offset > 0
+}
+
+// HasNext tells whether there are quotes after the Page
+func (p *Page) HasNext() bool {
+	return p.Offset+p.Limit < p.Total
+}
+
+// PrevOffset returns the offset of the previous Page
+func (p *Page) PrevOffset() int {
+	return max(p.Offset-p.Limit, 0)
+}
+
+// NextOffset returns the offset of the next Page
+func (p *Page) NextOffset() int {
+	return p.Offset + p.Limit
+}
+
+// LastOffset returns the offset of the last Page
+func (p *Page) LastOffset() int {
+	if p.Total == 0 || p.Limit <= 0 {
+		return 0
+	}
+	return (p.Total - 1) / p.Limit * p.Limit
+}
+
+// WriteHTML writes the HTML page of p with the built-in templates
+func (p *Page) WriteHTML(w io.Writer) error {
+	return defaultTemplates().ExecutePage(w, p, RenderOptions{})
+}
+
+func (p *Page) WriteJSON(w io.Writer) error {
+	return json.NewEncoder(w).Encode(p)
+}
+
+// WriteText writes the quotes in p as plain text, separated by blank lines.
+// Lines are wrapped to width characters, unless width is 0.
+func (p *Page) WriteText(w io.Writer, width int) error {
+	for i := range p.Quotes {
+		if i > 0 {
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+		if err := p.Quotes[i].WriteText(w, width); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteMarkdown writes the quotes in p as Markdown blockquotes
+func (p *Page) WriteMarkdown(w io.Writer) error {
+	for i := range p.Quotes {
+		if i > 0 {
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+		if err := p.Quotes[i].WriteMarkdown(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quote
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestNewPage(t *testing.T) {
+	qb := New()
+	qb.FillExample()
+	p, err := NewPage(qb, 2, 2)
+	if err != nil {
+		t.Fatalf("NewPage failed: %v", err)
+	}
+	if len(p.Quotes) != 2 || p.Quotes[0].ID != 2 {
+		t.Errorf("Unexpected quotes in page: %+v", p.Quotes)
+	}
+	if p.Total != len(qb.quoteList) {
+		t.Errorf("Expected total %d, got %d", len(qb.quoteList), p.Total)
+	}
+	if _, err := NewPage(qb, -1, 2); err == nil {
+		t.Error("Expected error for negative offset, got nil")
+	}
+}
+
+func TestPageOffsets(t *testing.T) {
+	tests := []struct {
+		page             Page
+		hasPrev, hasNext bool
+		prev, next, last int
+	}{
+		{Page{Total: 6, Offset: 0, Limit: 2}, false, true, 0, 2, 4},
+		{Page{Total: 6, Offset: 3, Limit: 2}, true, true, 1, 5, 4},
+		{Page{Total: 7, Offset: 6, Limit: 2}, true, false, 4, 8, 6},
+		{Page{Total: 0, Offset: 0, Limit: 2}, false, false, 0, 2, 0},
+	}
+	for _, tt := range tests {
+		p := tt.page
+		if p.HasPrev() != tt.hasPrev || p.HasNext() != tt.hasNext {
+			t.Errorf("%+v: expected prev/next %v/%v, got %v/%v", p, tt.hasPrev, tt.hasNext, p.HasPrev(), p.HasNext())
+		}
+		if p.PrevOffset() != tt.prev || p.NextOffset() != tt.next || p.LastOffset() != tt.last {
+			t.Errorf("%+v: expected offsets %d/%d/%d, got %d/%d/%d", p, tt.prev, tt.next, tt.last,
+				p.PrevOffset(), p.NextOffset(), p.LastOffset())
+		}
+	}
+}
+
+func TestPageWriteHTML(t *testing.T) {
+	p := Page{Quotes: []Quotation{{ID: 3, Quote: "Listed", Author: "Tester"}}, Total: 5, Offset: 3, Limit: 1}
+	var buf bytes.Buffer
+	if err := p.WriteHTML(&buf); err != nil {
+		t.Fatalf("WriteHTML error: %v", err)
+	}
+	for _, want := range []string{"Listed", `href="/quotes/3"`, "offset=2", "offset=4"} {
+		if !bytes.Contains(buf.Bytes(), []byte(want)) {
+			t.Errorf("Expected %q in HTML output: %s", want, buf.String())
+		}
+	}
+}
+
+func TestPageWriteJSON(t *testing.T) {
+	p := Page{Quotes: []Quotation{{ID: 3, Quote: "Listed", Author: "Tester"}}, Total: 5, Offset: 3, Limit: 1}
+	var buf bytes.Buffer
+	if err := p.WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON error: %v", err)
+	}
+	var got Page
+	if err := json.NewDecoder(&buf).Decode(&got); err != nil {
+		t.Fatalf("Decode error: %v", err)
+	}
+	if got.Total != p.Total || !reflect.DeepEqual(got.Quotes, p.Quotes) {
+		t.Errorf("Expected %+v, got %+v", p, got)
+	}
+}
+
+func TestPageWriteText(t *testing.T) {
+	p := Page{Quotes: []Quotation{{Quote: "One", Author: "A"}, {Quote: "Two"}}}
+	var buf bytes.Buffer
+	if err := p.WriteText(&buf, 0); err != nil {
+		t.Fatalf("WriteText error: %v", err)
+	}
+	want := "One\n— A\n\nTwo\n"
+	if buf.String() != want {
+		t.Errorf("Expected %q, got %q", want, buf.String())
+	}
+}
@@ -17,25 +17,134 @@ limitations under the License.
 package quote
 
 import (
+	"cmp"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"html/template"
 	"io"
+	"maps"
 	"math/rand"
+	"regexp"
+	"slices"
+	"strings"
 	"sync"
+	"unicode/utf8"
 )
 
 // Quotation contains the data of a single quote
 type Quotation struct {
-	Quote  string
+	// ID is assigned when the quote is added to a Store and never changes
+	ID     int      `json:"id" yaml:"id"`
+	Quote  string   `json:"quote" yaml:"quote"`
+	Author string   `json:"author" yaml:"author,omitempty"`
+	Tags   []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+// clone returns a deep copy of q, so that callers cannot modify
+// the Quotations held in a QuoteBook
+func (q Quotation) clone() Quotation {
+	q.Tags = slices.Clone(q.Tags)
+	return q
+}
+
+// normalizeTags returns a copy of tags in lower case, without blanks
+// and duplicates
+func normalizeTags(tags []string) []string {
+	var normalized []string
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !slices.Contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized
+}
+
+// HasTag tells whether q is tagged with tag, regardless of case
+func (q *Quotation) HasTag(tag string) bool {
+	return slices.ContainsFunc(q.Tags, func(t string) bool {
+		return strings.EqualFold(t, tag)
+	})
+}
+
+// Filter selects Quotations: empty fields match any quote
+type Filter struct {
+	Tag    string
 	Author string
 }
 
-const maxQuotes = 20
+// Match tells whether q is selected by f
+func (f Filter) Match(q *Quotation) bool {
+	if f.Tag != "" && !q.HasTag(strings.TrimSpace(f.Tag)) {
+		return false
+	}
+	if f.Author != "" && !strings.EqualFold(strings.TrimSpace(f.Author), q.Author) {
+		return false
+	}
+	return true
+}
+
+// TagCount reports how many quotes are tagged with Tag
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+const (
+	// maxQuotes is the default limit of quotes in a QuoteBook
+	maxQuotes = 20
+
+	maxQuoteLength  = 1000
+	maxAuthorLength = 200
+	maxTags         = 20
+)
+
+var (
+	// ErrNotFound is returned when the requested quote does not exist
+	ErrNotFound = errors.New("not found")
+	// ErrInvalid is returned when a quote cannot be stored as is
+	ErrInvalid = errors.New("invalid quote")
+	// ErrFull is returned when there is no room for more quotes
+	ErrFull = errors.New("QuoteBook is full")
+	// ErrClosed is returned when changing the quotes of a closed Store
+	ErrClosed = errors.New("store closed")
+)
 
-// QuoteBook is a collection of Quotations
+// Validate checks that q can be stored, returning an error wrapping
+// ErrInvalid if not
+func (q *Quotation) Validate() error {
+	switch {
+	case strings.TrimSpace(q.Quote) == "":
+		return fmt.Errorf("%w: empty quote", ErrInvalid)
+	case utf8.RuneCountInString(q.Quote) > maxQuoteLength:
+		return fmt.Errorf("%w: quote longer than %d characters", ErrInvalid, maxQuoteLength)
+	case utf8.RuneCountInString(q.Author) > maxAuthorLength:
+		return fmt.Errorf("%w: author longer than %d characters", ErrInvalid, maxAuthorLength)
+	case len(q.Tags) > maxTags:
+		return fmt.Errorf("%w: more than %d tags", ErrInvalid, maxTags)
+	case slices.ContainsFunc(q.Tags, func(tag string) bool { return strings.Contains(tag, ",") }):
+		// Commas separate tags, e.g., in CSV files
+		return fmt.Errorf("%w: tag with a comma", ErrInvalid)
+	}
+	return nil
+}
+
+// QuoteBook is a collection of Quotations kept in memory
 type QuoteBook struct {
+	// quoteList is always sorted by ID
 	quoteList []Quotation
+	nextID    int
+	// pins map keys, e.g., calendar days, to the IDs of the quotes
+	// pinned to them
+	pins map[string]int
+	// limit is the maximum number of quotes in the QuoteBook, maxQuotes
+	// if not set
+	limit int
+	// index is the search index of quoteList, built on first use
+	index *index
+	// persist, when set, is called with the updated content of the QuoteBook
+	// before any change is committed: if it fails the QuoteBook is left untouched
+	persist func(fileData) error
 	sync.Mutex
 }
 
@@ -43,71 +152,456 @@ func New() *QuoteBook {
 	return new(QuoteBook)
 }
 
+// SetMaxQuotes changes the maximum number of quotes in the QuoteBook,
+// which then refuses new ones. 0 restores the default of 20.
+func (q *QuoteBook) SetMaxQuotes(n int) {
+	q.Lock()
+	defer q.Unlock()
+	q.limit = n
+}
+
 func (q *QuoteBook) RandomQuotation() (*Quotation, error) {
+	return q.RandomQuotationMatching(Filter{})
+}
+
+// RandomQuotationMatching returns a random quote among the ones selected by filter
+func (q *QuoteBook) RandomQuotationMatching(filter Filter) (*Quotation, error) {
 	q.Lock()
 	defer q.Unlock()
 	if len(q.quoteList) == 0 {
-		return nil, fmt.Errorf("empty QuoteBook")
+		return nil, fmt.Errorf("%w: empty QuoteBook", ErrNotFound)
 	}
 
-	idx := rand.Intn(len(q.quoteList))
-	quote := q.quoteList[idx]
+	var matching []int
+	for idx := range q.quoteList {
+		if filter.Match(&q.quoteList[idx]) {
+			matching = append(matching, idx)
+		}
+	}
+	if len(matching) == 0 {
+		return nil, fmt.Errorf("%w: no quote matching the filter", ErrNotFound)
+	}
+
+	idx := matching[rand.Intn(len(matching))]
+	quote := q.quoteList[idx].clone()
 	return &quote, nil
 }
 
+// Tags returns the tags of the quotes with the number of quotes tagged
+// with each one, sorted by decreasing count
+func (q *QuoteBook) Tags() []TagCount {
+	q.Lock()
+	defer q.Unlock()
+
+	counts := map[string]int{}
+	for _, quote := range q.quoteList {
+		for _, tag := range quote.Tags {
+			counts[tag]++
+		}
+	}
+
+	tags := make([]TagCount, 0, len(counts))
+	for tag, count := range counts {
+		tags = append(tags, TagCount{Tag: tag, Count: count})
+	}
+	slices.SortFunc(tags, func(a, b TagCount) int {
+		if c := cmp.Compare(b.Count, a.Count); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Tag, b.Tag)
+	})
+	return tags
+}
+
 func (q *QuoteBook) FillExample() {
 	q.quoteList = []Quotation{
-		{"Start before you are ready. Don't prepare, begin.", "Mel Robbins"},
-		{"Eat the frog first.", "Brian Tracy"},
-		{"Imperfect action beats perfect inaction.", "Harry S. Truman"},
-		{"Succeed or survive (but try).", "Mel Robbins"},
-		{"Be responsible for telling people the truth, not managing people's reactions to it.", "Mel Robbins"},
-		{"Today's favor is tomorrow's expectation.", "Mel Robbins"},
+		{Quote: "Start before you are ready. Don't prepare, begin.", Author: "Mel Robbins", Tags: []string{"productivity", "action"}},
+		{Quote: "Eat the frog first.", Author: "Brian Tracy", Tags: []string{"productivity"}},
+		{Quote: "Imperfect action beats perfect inaction.", Author: "Harry S. Truman", Tags: []string{"action"}},
+		{Quote: "Succeed or survive (but try).", Author: "Mel Robbins", Tags: []string{"motivation"}},
+		{Quote: "Be responsible for telling people the truth, not managing people's reactions to it.", Author: "Mel Robbins", Tags: []string{"leadership"}},
+		{Quote: "Today's favor is tomorrow's expectation.", Author: "Mel Robbins", Tags: []string{"relationships"}},
+	}
+	for i := range q.quoteList {
+		q.quoteList[i].ID = i
+	}
+	q.nextID = len(q.quoteList)
+	q.pins = nil
+	q.index = nil
+}
+
+// AddQuote adds quote to the QuoteBook assigning it a new ID
+// and returns the stored Quotation
+func (q *QuoteBook) AddQuote(quote Quotation) (*Quotation, error) {
+	added, err := q.AddQuotes([]Quotation{quote})
+	if err != nil {
+		return nil, err
 	}
+	return &added[0], nil
+}
+
+// AddQuotes adds all the given quotes to the QuoteBook, assigning them
+// new IDs, or none of them if any is invalid or they do not fit.
+// It returns the stored Quotations.
+func (q *QuoteBook) AddQuotes(quotes []Quotation) ([]Quotation, error) {
+	return q.addQuotes(quotes, false)
 }
 
-func (q *QuoteBook) AddQuote(quote Quotation) error {
+// RestoreQuotes adds all the given quotes to the QuoteBook keeping their
+// IDs, or none of them if any is invalid, has an ID in use or they do not
+// fit. It returns the stored Quotations.
+func (q *QuoteBook) RestoreQuotes(quotes []Quotation) ([]Quotation, error) {
+	return q.addQuotes(quotes, true)
+}
+
+func (q *QuoteBook) addQuotes(quotes []Quotation, keepIDs bool) ([]Quotation, error) {
 	q.Lock()
 	defer q.Unlock()
-	if len(q.quoteList) > maxQuotes {
-		return fmt.Errorf("QuoteBook is full")
+	quoteList, added, nextID, err := q.prepareQuotes(quotes, keepIDs)
+	if err != nil {
+		return nil, err
 	}
-	q.quoteList = append(q.quoteList, quote)
-	return nil
+	if err := q.commit(quoteList, nextID); err != nil {
+		return nil, err
+	}
+
+	if q.index != nil {
+		for i := range added {
+			q.index.add(&added[i])
+		}
+	}
+	result := make([]Quotation, 0, len(added))
+	for _, quote := range added {
+		result = append(result, quote.clone())
+	}
+	return result, nil
 }
 
-func (q *QuoteBook) GetQuote(num int) (*Quotation, error) {
+// CheckQuotes returns the error AddQuotes, or RestoreQuotes if keepIDs,
+// would return for quotes, without adding them
+func (q *QuoteBook) CheckQuotes(quotes []Quotation, keepIDs bool) error {
 	q.Lock()
 	defer q.Unlock()
-	if len(q.quoteList) == 0 {
-		return nil, fmt.Errorf("empty QuoteBook")
+	_, _, _, err := q.prepareQuotes(quotes, keepIDs)
+	return err
+}
+
+// prepareQuotes returns the quotes of the QuoteBook with quotes added, the
+// added ones and the next ID to assign, without committing them. Must be
+// called with the lock held.
+func (q *QuoteBook) prepareQuotes(quotes []Quotation, keepIDs bool) ([]Quotation, []Quotation, int, error) {
+	limit := q.limit
+	if limit == 0 {
+		limit = maxQuotes
 	}
-	if num >= len(q.quoteList) {
-		return nil, fmt.Errorf("id %d out of bounds", num)
+	if len(q.quoteList)+len(quotes) > limit {
+		return nil, nil, 0, ErrFull
 	}
-	quote := q.quoteList[num]
-	return &quote, nil
+
+	quoteList := q.quoteList
+	if keepIDs {
+		// quoteList is sorted again below, which must not affect q.quoteList
+		quoteList = slices.Clone(q.quoteList)
+	}
+	added := make([]Quotation, 0, len(quotes))
+	nextID := q.nextID
+	ids := map[int]bool{}
+	for i, quote := range quotes {
+		err := quote.Validate()
+		if err == nil && keepIDs {
+			err = q.checkID(quote.ID, ids)
+		}
+		if err != nil {
+			if len(quotes) > 1 {
+				return nil, nil, 0, fmt.Errorf("quote %d: %w", i, err)
+			}
+			return nil, nil, 0, err
+		}
+		if keepIDs {
+			nextID = max(nextID, quote.ID+1)
+		} else {
+			quote.ID = nextID
+			nextID++
+		}
+		quote.Tags = normalizeTags(quote.Tags)
+		quoteList = append(quoteList, quote)
+		added = append(added, quote)
+	}
+	if keepIDs {
+		slices.SortFunc(quoteList, func(a, b Quotation) int {
+			return cmp.Compare(a.ID, b.ID)
+		})
+	}
+	return quoteList, added, nextID, nil
 }
 
-func (q *Quotation) WriteHTML(w io.Writer) error {
-	const tpl = `
-<!DOCTYPE html>
-<html>
-<body>
+// checkID returns an error wrapping ErrInvalid if a quote cannot be added
+// with id, because it is negative, in use or among the IDs already added.
+// Must be called with the lock held.
+func (q *QuoteBook) checkID(id int, added map[int]bool) error {
+	if id < 0 {
+		return fmt.Errorf("%w: negative id %d", ErrInvalid, id)
+	}
+	if _, err := q.find(id); err == nil || added[id] {
+		return fmt.Errorf("%w: id %d already in use", ErrInvalid, id)
+	}
+	added[id] = true
+	return nil
+}
 
-<q style=font-size:200%;font-family:cursive>{{.Quote}}</q>
-<p><i>{{.Author}}</i></p>
+func (q *QuoteBook) GetQuote(id int) (*Quotation, error) {
+	q.Lock()
+	defer q.Unlock()
+	idx, err := q.find(id)
+	if err != nil {
+		return nil, err
+	}
+	quote := q.quoteList[idx].clone()
+	return &quote, nil
+}
 
-</body>
-</html>`
+// UpdateQuote replaces the quote identified by id, which is preserved
+func (q *QuoteBook) UpdateQuote(id int, quote Quotation) error {
+	_, err := q.PatchQuote(id, func(stored *Quotation) error {
+		*stored = quote
+		return nil
+	})
+	return err
+}
 
-	tmpl, err := template.New("html").Parse(tpl)
+// PatchQuote changes the quote identified by id with patch, which is called
+// with the lock held, and returns the stored Quotation. The ID is preserved.
+func (q *QuoteBook) PatchQuote(id int, patch func(*Quotation) error) (*Quotation, error) {
+	q.Lock()
+	defer q.Unlock()
+	idx, err := q.find(id)
 	if err != nil {
+		return nil, err
+	}
+	quote := q.quoteList[idx].clone()
+	if err := patch(&quote); err != nil {
+		return nil, err
+	}
+	if err := quote.Validate(); err != nil {
+		return nil, err
+	}
+	quote.ID = id
+	quote.Tags = normalizeTags(quote.Tags)
+	quoteList := slices.Clone(q.quoteList)
+	quoteList[idx] = quote
+	if err := q.commit(quoteList, q.nextID); err != nil {
+		return nil, err
+	}
+	if q.index != nil {
+		q.index.remove(id)
+		q.index.add(&quote)
+	}
+	stored := quote.clone()
+	return &stored, nil
+}
+
+func (q *QuoteBook) DeleteQuote(id int) error {
+	q.Lock()
+	defer q.Unlock()
+	idx, err := q.find(id)
+	if err != nil {
+		return err
+	}
+	if err := q.commit(slices.Delete(slices.Clone(q.quoteList), idx, idx+1), q.nextID); err != nil {
 		return err
 	}
-	return tmpl.Execute(w, q)
+	if q.index != nil {
+		q.index.remove(id)
+	}
+	return nil
+}
+
+// ListQuotes returns up to limit quotes starting from offset,
+// or all the remaining ones if limit is not positive
+func (q *QuoteBook) ListQuotes(offset, limit int) ([]Quotation, error) {
+	q.Lock()
+	defer q.Unlock()
+	if offset < 0 {
+		return nil, fmt.Errorf("invalid offset %d", offset)
+	}
+	if offset >= len(q.quoteList) {
+		return []Quotation{}, nil
+	}
+	end := len(q.quoteList)
+	if limit > 0 && offset+limit < end {
+		end = offset + limit
+	}
+	quotes := make([]Quotation, 0, end-offset)
+	for _, quote := range q.quoteList[offset:end] {
+		quotes = append(quotes, quote.clone())
+	}
+	return quotes, nil
+}
+
+// ListQuotesAfter returns up to limit quotes with an ID greater than
+// afterID, or all of them if limit is not positive
+func (q *QuoteBook) ListQuotesAfter(afterID, limit int) ([]Quotation, error) {
+	q.Lock()
+	defer q.Unlock()
+	start, found := slices.BinarySearchFunc(q.quoteList, afterID, func(quote Quotation, id int) int {
+		return cmp.Compare(quote.ID, id)
+	})
+	if found {
+		start++
+	}
+	end := len(q.quoteList)
+	if limit > 0 && start+limit < end {
+		end = start + limit
+	}
+	quotes := make([]Quotation, 0, end-start)
+	for _, quote := range q.quoteList[start:end] {
+		quotes = append(quotes, quote.clone())
+	}
+	return quotes, nil
+}
+
+func (q *QuoteBook) Count() int {
+	q.Lock()
+	defer q.Unlock()
+	return len(q.quoteList)
+}
+
+// maxPins is the number of pins kept by a QuoteBook, enough for the quotes
+// of the day of more than a year
+const maxPins = 1000
+
+// PinnedQuotes returns the quotes pinned to keys. Keys without a pinned
+// quote, or whose quote was deleted, get pinned the quote at position
+// choose(key, count) among the count stored ones, so that adding or
+// deleting other quotes does not change them. Only the last maxPins keys
+// in lexical order are kept.
+func (q *QuoteBook) PinnedQuotes(keys []string, choose func(key string, count int) int) ([]Quotation, error) {
+	q.Lock()
+	defer q.Unlock()
+	if len(q.quoteList) == 0 {
+		return nil, fmt.Errorf("%w: empty QuoteBook", ErrNotFound)
+	}
+
+	pins := q.pins
+	changed := false
+	quotes := make([]Quotation, 0, len(keys))
+	for _, key := range keys {
+		if id, ok := pins[key]; ok {
+			if idx, err := q.find(id); err == nil {
+				quotes = append(quotes, q.quoteList[idx].clone())
+				continue
+			}
+		}
+		if !changed {
+			// Pins are copied, as they are persisted before being changed
+			pins = make(map[string]int, len(q.pins)+len(keys))
+			maps.Copy(pins, q.pins)
+			changed = true
+		}
+		quote := q.quoteList[choose(key, len(q.quoteList))]
+		pins[key] = quote.ID
+		quotes = append(quotes, quote.clone())
+	}
+	if !changed {
+		return quotes, nil
+	}
+
+	if len(pins) > maxPins {
+		for _, key := range slices.Sorted(maps.Keys(pins))[:len(pins)-maxPins] {
+			delete(pins, key)
+		}
+	}
+	// Pins are kept in memory even if they cannot be persisted, as serving
+	// the quotes matters more
+	if q.persist != nil {
+		_ = q.persist(fileData{NextID: q.nextID, Quotes: q.quoteList, Pins: pins})
+	}
+	q.pins = pins
+	return quotes, nil
+}
+
+// find returns the position of the quote identified by id.
+// Must be called with the lock held.
+func (q *QuoteBook) find(id int) (int, error) {
+	idx, found := slices.BinarySearchFunc(q.quoteList, id, func(quote Quotation, id int) int {
+		return cmp.Compare(quote.ID, id)
+	})
+	if !found {
+		return 0, fmt.Errorf("id %d %w", id, ErrNotFound)
+	}
+	return idx, nil
+}
+
+// commit replaces the content of the QuoteBook, persisting it first
+// when required. Must be called with the lock held.
+func (q *QuoteBook) commit(quoteList []Quotation, nextID int) error {
+	if q.persist != nil {
+		if err := q.persist(fileData{NextID: nextID, Quotes: quoteList, Pins: q.pins}); err != nil {
+			return fmt.Errorf("cannot store quotes: %w", err)
+		}
+	}
+	q.quoteList = quoteList
+	q.nextID = nextID
+	return nil
+}
+
+// WriteHTML writes the HTML page of q with the built-in templates
+func (q *Quotation) WriteHTML(w io.Writer) error {
+	return defaultTemplates().ExecuteQuote(w, q, RenderOptions{})
 }
 
 func (q *Quotation) WriteJSON(w io.Writer) error {
 	return json.NewEncoder(w).Encode(q)
 }
+
+// WriteText writes q as plain text, followed by the author on its own line.
+// Lines are wrapped to width characters, unless width is 0.
+func (q *Quotation) WriteText(w io.Writer, width int) error {
+	var b strings.Builder
+	for _, line := range wrapText(q.Quote, width) {
+		b.WriteString(line + "\n")
+	}
+	if q.Author != "" {
+		for _, line := range wrapText("— "+q.Author, width) {
+			b.WriteString(line + "\n")
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteMarkdown writes q as a Markdown blockquote followed by the author
+func (q *Quotation) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	for _, line := range strings.Split(q.Quote, "\n") {
+		b.WriteString(strings.TrimRight("> "+escapeMarkdown(line), " ") + "\n")
+	}
+	if q.Author != "" {
+		b.WriteString(">\n> — " + escapeMarkdown(q.Author) + "\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// markdownEscaper escapes the characters with a meaning anywhere in Markdown
+// text
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", `*`, `\*`, `_`, `\_`, `[`, `\[`, `]`, `\]`,
+	`<`, `\<`, `>`, `\>`, `&`, `\&`, `~`, `\~`, `|`, `\|`,
+)
+
+var (
+	// markdownBlockMarker matches the headings and the bullet list items
+	// a line can start with
+	markdownBlockMarker = regexp.MustCompile(`^(\s*)([#+=-])`)
+	// markdownOrderedItem matches the ordered list items a line can
+	// start with
+	markdownOrderedItem = regexp.MustCompile(`^(\s*\d+)([.)])`)
+)
+
+// escapeMarkdown escapes line so that it is rendered as is in Markdown
+func escapeMarkdown(line string) string {
+	line = markdownEscaper.Replace(line)
+	line = markdownBlockMarker.ReplaceAllString(line, `$1\$2`)
+	return markdownOrderedItem.ReplaceAllString(line, `$1\$2`)
+}
@@ -20,7 +20,11 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"reflect"
+	"slices"
+	"strings"
 	"sync"
 	"testing"
 )
@@ -42,23 +46,27 @@ func TestNewQuoteBook(t *testing.T) {
 func TestAddQuoteAndGetQuote(t *testing.T) {
 	qb := New()
 	q := Quotation{Quote: "Hello", Author: "World"}
-	if err := qb.AddQuote(q); err != nil {
+	added, err := qb.AddQuote(q)
+	if err != nil {
 		t.Fatalf("AddQuote failed: %v", err)
 	}
-	got, err := qb.GetQuote(0)
+	if added.ID != 0 {
+		t.Errorf("Expected first quote to get id 0, got %d", added.ID)
+	}
+	got, err := qb.GetQuote(added.ID)
 	if err != nil {
 		t.Fatalf("GetQuote failed: %v", err)
 	}
-	if *got != q {
+	if !reflect.DeepEqual(*got, q) {
 		t.Errorf("Expected %+v, got %+v", q, *got)
 	}
 }
 
 func TestAddQuote_FullBook(t *testing.T) {
 	qb := New()
-	for i := 0; i <= maxQuotes+1; i++ {
-		err := qb.AddQuote(Quotation{Quote: "Q", Author: "A"})
-		if i <= maxQuotes {
+	for i := 0; i <= maxQuotes; i++ {
+		_, err := qb.AddQuote(Quotation{Quote: "Q", Author: "A"})
+		if i < maxQuotes {
 			if err != nil {
 				t.Fatalf("Unexpected error before full: %v", err)
 			}
@@ -70,6 +78,95 @@ func TestAddQuote_FullBook(t *testing.T) {
 	}
 }
 
+func TestAddQuote_Invalid(t *testing.T) {
+	qb := New()
+	for _, q := range []Quotation{
+		{Quote: "  ", Author: "Nobody"},
+		{Quote: strings.Repeat("x", maxQuoteLength+1)},
+		{Quote: "Q", Author: strings.Repeat("x", maxAuthorLength+1)},
+		{Quote: "Q", Tags: []string{"comma, separated"}},
+	} {
+		if _, err := qb.AddQuote(q); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Expected ErrInvalid adding %.20q, got %v", q.Quote, err)
+		}
+	}
+	if qb.Count() != 0 {
+		t.Errorf("Expected no quotes to be added, got %d", qb.Count())
+	}
+}
+
+func TestAddQuotes(t *testing.T) {
+	qb := New()
+	qb.FillExample()
+	added, err := qb.AddQuotes([]Quotation{{Quote: "A"}, {Quote: "B"}})
+	if err != nil {
+		t.Fatalf("AddQuotes failed: %v", err)
+	}
+	if len(added) != 2 || added[0].ID != 6 || added[1].ID != 7 {
+		t.Errorf("Unexpected quotes added: %+v", added)
+	}
+
+	// All or nothing
+	if _, err := qb.AddQuotes([]Quotation{{Quote: "C"}, {Quote: ""}}); !errors.Is(err, ErrInvalid) {
+		t.Errorf("Expected ErrInvalid, got %v", err)
+	}
+	qb.SetMaxQuotes(9)
+	if _, err := qb.AddQuotes([]Quotation{{Quote: "C"}, {Quote: "D"}}); !errors.Is(err, ErrFull) {
+		t.Errorf("Expected ErrFull, got %v", err)
+	}
+	if qb.Count() != 8 {
+		t.Errorf("Expected 8 quotes, got %d", qb.Count())
+	}
+	if _, err := qb.AddQuotes([]Quotation{{Quote: "C"}}); err != nil {
+		t.Errorf("Expected room for a last quote, got %v", err)
+	}
+	if qb.Count() != 9 {
+		t.Errorf("Expected 9 quotes, got %d", qb.Count())
+	}
+}
+
+func TestRestoreQuotes(t *testing.T) {
+	qb := New()
+	qb.FillExample()
+	_ = qb.DeleteQuote(2)
+	restored, err := qb.RestoreQuotes([]Quotation{{ID: 10, Quote: "A"}, {ID: 2, Quote: "B"}})
+	if err != nil {
+		t.Fatalf("RestoreQuotes failed: %v", err)
+	}
+	if len(restored) != 2 || restored[0].ID != 10 || restored[1].ID != 2 {
+		t.Errorf("Unexpected quotes restored: %+v", restored)
+	}
+	for i := 1; i < len(qb.quoteList); i++ {
+		if qb.quoteList[i-1].ID >= qb.quoteList[i].ID {
+			t.Fatalf("Quotes not sorted by ID: %+v", qb.quoteList)
+		}
+	}
+	if q, err := qb.GetQuote(2); err != nil || q.Quote != "B" {
+		t.Errorf("Expected the restored quote 2, got %+v, %v", q, err)
+	}
+	// IDs are assigned after the restored ones
+	if added, _ := qb.AddQuote(Quotation{Quote: "C"}); added == nil || added.ID != 11 {
+		t.Errorf("Expected ID 11 after the restored quotes, got %+v", added)
+	}
+
+	// All or nothing
+	for _, quotes := range [][]Quotation{
+		{{ID: 20, Quote: "D"}, {ID: 1, Quote: "In use"}},
+		{{ID: 20, Quote: "D"}, {ID: 20, Quote: "Duplicate"}},
+		{{ID: 20, Quote: "D"}, {ID: -1, Quote: "Negative"}},
+	} {
+		if err := qb.CheckQuotes(quotes, true); !errors.Is(err, ErrInvalid) {
+			t.Errorf("CheckQuotes(%+v): expected ErrInvalid, got %v", quotes, err)
+		}
+		if _, err := qb.RestoreQuotes(quotes); !errors.Is(err, ErrInvalid) {
+			t.Errorf("RestoreQuotes(%+v): expected ErrInvalid, got %v", quotes, err)
+		}
+	}
+	if qb.Count() != 8 {
+		t.Errorf("Expected 8 quotes, got %d", qb.Count())
+	}
+}
+
 func TestGetQuote_Errors(t *testing.T) {
 	qb := New()
 	// Empty book
@@ -77,11 +174,128 @@ func TestGetQuote_Errors(t *testing.T) {
 	if err == nil {
 		t.Error("Expected error for empty QuoteBook, got nil")
 	}
-	// Out-of-bounds
-	_ = qb.AddQuote(Quotation{Quote: "A", Author: "B"})
+	// Not existing id
+	_, _ = qb.AddQuote(Quotation{Quote: "A", Author: "B"})
 	_, err = qb.GetQuote(2)
 	if err == nil {
-		t.Error("Expected error for out-of-bounds index, got nil")
+		t.Error("Expected error for not existing id, got nil")
+	}
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestUpdateQuote(t *testing.T) {
+	qb := New()
+	qb.FillExample()
+	q := Quotation{Quote: "Updated", Author: "Tester"}
+	if err := qb.UpdateQuote(1, q); err != nil {
+		t.Fatalf("UpdateQuote failed: %v", err)
+	}
+	got, err := qb.GetQuote(1)
+	if err != nil {
+		t.Fatalf("GetQuote failed: %v", err)
+	}
+	if got.ID != 1 || got.Quote != q.Quote || got.Author != q.Author {
+		t.Errorf("Expected %+v with id 1, got %+v", q, *got)
+	}
+	if err := qb.UpdateQuote(-1, q); err == nil {
+		t.Error("Expected error updating a negative id, got nil")
+	}
+}
+
+func TestDeleteQuote(t *testing.T) {
+	qb := New()
+	qb.FillExample()
+	count := qb.Count()
+	if err := qb.DeleteQuote(0); err != nil {
+		t.Fatalf("DeleteQuote failed: %v", err)
+	}
+	if qb.Count() != count-1 {
+		t.Errorf("Expected %d quotes, got %d", count-1, qb.Count())
+	}
+	if err := qb.DeleteQuote(0); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound deleting a deleted quote, got %v", err)
+	}
+	// IDs of the other quotes must not change
+	got, err := qb.GetQuote(1)
+	if err != nil {
+		t.Fatalf("GetQuote failed: %v", err)
+	}
+	if got.ID != 1 || got.Quote != "Eat the frog first." {
+		t.Errorf("Unexpected quote with id 1 after delete: %+v", got)
+	}
+}
+
+func TestAddQuote_IDsNotReused(t *testing.T) {
+	qb := New()
+	first, _ := qb.AddQuote(Quotation{Quote: "A", Author: "B"})
+	if err := qb.DeleteQuote(first.ID); err != nil {
+		t.Fatalf("DeleteQuote failed: %v", err)
+	}
+	second, err := qb.AddQuote(Quotation{Quote: "C", Author: "D"})
+	if err != nil {
+		t.Fatalf("AddQuote failed: %v", err)
+	}
+	if second.ID == first.ID {
+		t.Errorf("Expected a new id, got %d again", second.ID)
+	}
+}
+
+func TestListQuotes(t *testing.T) {
+	qb := New()
+	qb.FillExample()
+	tests := []struct {
+		offset, limit int
+		want          int
+	}{
+		{0, 0, len(qb.quoteList)},
+		{0, 2, 2},
+		{4, 10, len(qb.quoteList) - 4},
+		{100, 2, 0},
+	}
+	for _, tt := range tests {
+		got, err := qb.ListQuotes(tt.offset, tt.limit)
+		if err != nil {
+			t.Errorf("ListQuotes(%d, %d) failed: %v", tt.offset, tt.limit, err)
+			continue
+		}
+		if len(got) != tt.want {
+			t.Errorf("ListQuotes(%d, %d): expected %d quotes, got %d", tt.offset, tt.limit, tt.want, len(got))
+		}
+	}
+	if _, err := qb.ListQuotes(-1, 0); err == nil {
+		t.Error("Expected error for negative offset, got nil")
+	}
+}
+
+func TestListQuotesAfter(t *testing.T) {
+	qb := New()
+	qb.FillExample()
+	_ = qb.DeleteQuote(2)
+	tests := []struct {
+		afterID, limit int
+		want           []int
+	}{
+		{-1, 0, []int{0, 1, 3, 4, 5}},
+		{-1, 2, []int{0, 1}},
+		{1, 2, []int{3, 4}},
+		{2, 0, []int{3, 4, 5}},
+		{5, 2, []int{}},
+	}
+	for _, tt := range tests {
+		got, err := qb.ListQuotesAfter(tt.afterID, tt.limit)
+		if err != nil {
+			t.Errorf("ListQuotesAfter(%d, %d) failed: %v", tt.afterID, tt.limit, err)
+			continue
+		}
+		ids := []int{}
+		for _, q := range got {
+			ids = append(ids, q.ID)
+		}
+		if !reflect.DeepEqual(ids, tt.want) {
+			t.Errorf("ListQuotesAfter(%d, %d): expected IDs %v, got %v", tt.afterID, tt.limit, tt.want, ids)
+		}
 	}
 }
 
@@ -100,7 +314,7 @@ func TestRandomQuotation(t *testing.T) {
 	}
 	found := false
 	for _, q := range qb.quoteList {
-		if *got == q {
+		if reflect.DeepEqual(*got, q) {
 			found = true
 			break
 		}
@@ -110,6 +324,71 @@ func TestRandomQuotation(t *testing.T) {
 	}
 }
 
+func TestAddQuote_NormalizesTags(t *testing.T) {
+	qb := New()
+	added, err := qb.AddQuote(Quotation{Quote: "Q", Author: "A", Tags: []string{" Focus", "focus", "", "Work "}})
+	if err != nil {
+		t.Fatalf("AddQuote failed: %v", err)
+	}
+	want := []string{"focus", "work"}
+	if !reflect.DeepEqual(added.Tags, want) {
+		t.Errorf("Expected tags %v, got %v", want, added.Tags)
+	}
+
+	// Returned quotes must not share memory with the stored ones
+	added.Tags[0] = "changed"
+	got, _ := qb.GetQuote(added.ID)
+	if !reflect.DeepEqual(got.Tags, want) {
+		t.Errorf("Stored quote was modified: %v", got.Tags)
+	}
+}
+
+func TestRandomQuotationMatching(t *testing.T) {
+	qb := New()
+	qb.FillExample()
+	tests := []struct {
+		filter Filter
+		want   []int
+	}{
+		{Filter{Tag: "productivity"}, []int{0, 1}},
+		{Filter{Tag: "Action"}, []int{0, 2}},
+		{Filter{Author: "brian tracy"}, []int{1}},
+		{Filter{Tag: "action", Author: "Mel Robbins"}, []int{0}},
+	}
+	for _, tt := range tests {
+		for i := 0; i < 10; i++ {
+			got, err := qb.RandomQuotationMatching(tt.filter)
+			if err != nil {
+				t.Fatalf("%+v: unexpected error: %v", tt.filter, err)
+			}
+			if !slices.Contains(tt.want, got.ID) {
+				t.Errorf("%+v: unexpected quote %+v", tt.filter, got)
+			}
+		}
+	}
+
+	_, err := qb.RandomQuotationMatching(Filter{Tag: "missing"})
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a missing tag, got %v", err)
+	}
+}
+
+func TestTags(t *testing.T) {
+	qb := New()
+	qb.FillExample()
+	got := qb.Tags()
+	want := []TagCount{
+		{"action", 2},
+		{"productivity", 2},
+		{"leadership", 1},
+		{"motivation", 1},
+		{"relationships", 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
 func TestWriteHTML(t *testing.T) {
 	q := Quotation{Quote: "HTML", Author: "Tester"}
 	var buf bytes.Buffer
@@ -141,7 +420,7 @@ func TestWriteJSON(t *testing.T) {
 	if err := json.NewDecoder(&buf).Decode(&got); err != nil {
 		t.Fatalf("Decode error: %v", err)
 	}
-	if got != q {
+	if !reflect.DeepEqual(got, q) {
 		t.Errorf("Expected %+v, got %+v", q, got)
 	}
 }
@@ -164,7 +443,8 @@ func TestConcurrentAddQuote(t *testing.T) {
 		wg.Add(1)
 		go func(i int) {
 			defer wg.Done()
-			errCh <- qb.AddQuote(Quotation{Quote: "Q", Author: "A"})
+			_, err := qb.AddQuote(Quotation{Quote: "Q", Author: "A"})
+			errCh <- err
 		}(i)
 	}
 	wg.Wait()
@@ -178,14 +458,53 @@ func TestConcurrentAddQuote(t *testing.T) {
 			countOK++
 		}
 	}
-	if countOK != maxQuotes+1 { // The last allowed add is index maxQuotes, then error on next
-		t.Errorf("Expected %d successful adds, got %d", maxQuotes+1, countOK)
+	if countOK != maxQuotes {
+		t.Errorf("Expected %d successful adds, got %d", maxQuotes, countOK)
 	}
 	if countErrors == 0 {
 		t.Error("Expected errors adding quotes concurrently beyond maxQuotes")
 	}
 }
 
+func TestConcurrentPatchQuote(t *testing.T) {
+	qb := New()
+	qb.FillExample()
+	var wg sync.WaitGroup
+	for i := range 15 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, _ = qb.PatchQuote(1, func(q *Quotation) error {
+				q.Author = fmt.Sprintf("Author %d", i)
+				return nil
+			})
+		}()
+		go func() {
+			defer wg.Done()
+			_, _ = qb.PatchQuote(1, func(q *Quotation) error {
+				q.Tags = append(q.Tags, fmt.Sprintf("tag%d", i))
+				return nil
+			})
+		}()
+	}
+	wg.Wait()
+	q, _ := qb.GetQuote(1)
+	// Every tag is kept, along with the original one
+	if len(q.Tags) != 16 {
+		t.Errorf("Expected 16 tags, got %d: %v", len(q.Tags), q.Tags)
+	}
+
+	if _, err := qb.PatchQuote(1, func(q *Quotation) error {
+		q.Quote = ""
+		return nil
+	}); !errors.Is(err, ErrInvalid) {
+		t.Errorf("Expected ErrInvalid, got %v", err)
+	}
+	if _, err := qb.PatchQuote(100, func(*Quotation) error { return nil }); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
 func TestConcurrentRandomAndAdd(t *testing.T) {
 	qb := New()
 	qb.FillExample()
@@ -202,7 +521,7 @@ func TestConcurrentRandomAndAdd(t *testing.T) {
 		}()
 		go func() {
 			defer wg.Done()
-			err := qb.AddQuote(Quotation{Quote: "C", Author: "D"})
+			_, err := qb.AddQuote(Quotation{Quote: "C", Author: "D"})
 			if err != nil && !errors.Is(err, nil) {
 				addErrs++
 			}
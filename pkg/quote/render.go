@@ -0,0 +1,241 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quote
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"slices"
+)
+
+// RenderOptions are the settings a client can choose for each rendering
+type RenderOptions struct {
+	// Width is the number of characters text is wrapped to, 0 not to wrap it
+	Width int
+	// Theme is the color theme of HTML pages, one of Themes
+	Theme string
+	// BaseURL is the absolute URL quotes are served at, used to link them
+	// from HTML pages
+	BaseURL string
+}
+
+// Renderer writes quotes in a media type
+type Renderer interface {
+	// RenderQuote writes a single quote
+	RenderQuote(w io.Writer, q *Quotation, opts RenderOptions) error
+}
+
+// PageRenderer is a Renderer which can also write a Page of quotes
+type PageRenderer interface {
+	Renderer
+	// RenderPage writes a Page of quotes
+	RenderPage(w io.Writer, p *Page, opts RenderOptions) error
+}
+
+// EmbedRenderer is a Renderer which can also write a quote to embed in
+// other pages
+type EmbedRenderer interface {
+	Renderer
+	// RenderEmbed writes a single quote to embed in other pages
+	RenderEmbed(w io.Writer, q *Quotation, opts RenderOptions) error
+}
+
+// registration is a Renderer registered in a Registry
+type registration struct {
+	name        string
+	contentType string
+	mediaType   string
+	renderer    Renderer
+}
+
+// Registry maps media types to the Renderers writing quotes in them.
+// Renderers must all be registered before the Registry is used, as
+// registration is not safe for concurrent use. The zero value is an empty
+// Registry ready to use.
+type Registry struct {
+	// registrations are in registration order
+	registrations []registration
+}
+
+// NewRegistry returns a Registry with all the built-in Renderers
+func NewRegistry() *Registry {
+	r := &Registry{}
+	r.Register("json", "application/json; charset=UTF-8", jsonRenderer{})
+	r.RegisterTemplates(defaultTemplates())
+	r.Register("text", "text/plain; charset=UTF-8", textRenderer{})
+	r.Register("markdown", "text/markdown; charset=UTF-8", markdownRenderer{})
+	if err := r.RegisterCards(DefaultCardStyle()); err != nil {
+		// The default style and font are always valid
+		panic(err)
+	}
+	return r
+}
+
+// Register makes renderer write quotes in the media type of contentType,
+// replacing any Renderer already registered for it. The parameters of
+// contentType, e.g., the charset, are sent to clients along with the
+// media type. Media types registered first are preferred when clients
+// accept many of them equally. name is a short name clients can use to
+// pick the Renderer instead of the media type.
+func (r *Registry) Register(name, contentType string, renderer Renderer) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		panic(fmt.Sprintf("invalid content type %q: %s", contentType, err))
+	}
+	reg := registration{
+		name:        name,
+		contentType: contentType,
+		mediaType:   mediaType,
+		renderer:    renderer,
+	}
+	if i := slices.IndexFunc(r.registrations, func(reg registration) bool {
+		return reg.mediaType == mediaType
+	}); i >= 0 {
+		r.registrations[i] = reg
+	} else {
+		r.registrations = append(r.registrations, reg)
+	}
+}
+
+// RegisterTemplates registers the Renderer of HTML pages using the given
+// Templates
+func (r *Registry) RegisterTemplates(t *Templates) {
+	r.Register("html", "text/html; charset=UTF-8", htmlRenderer{templates: t})
+}
+
+// RegisterCards registers the Renderers of PNG and SVG image cards
+// with the given style
+func (r *Registry) RegisterCards(style CardStyle) error {
+	pngRenderer, err := NewPNGRenderer(style)
+	if err != nil {
+		return err
+	}
+	svgRenderer, err := NewSVGRenderer(style)
+	if err != nil {
+		return err
+	}
+	r.Register("png", "image/png", pngRenderer)
+	r.Register("svg", "image/svg+xml; charset=UTF-8", svgRenderer)
+	return nil
+}
+
+// Lookup returns the Renderer registered for the media type of contentType
+func (r *Registry) Lookup(contentType string) (Renderer, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, false
+	}
+	for _, reg := range r.registrations {
+		if reg.mediaType == mediaType {
+			return reg.renderer, true
+		}
+	}
+	return nil, false
+}
+
+// LookupFormat returns the content type of the Renderer registered
+// with the given name
+func (r *Registry) LookupFormat(name string) (string, bool) {
+	for _, reg := range r.registrations {
+		if reg.name == name {
+			return reg.contentType, true
+		}
+	}
+	return "", false
+}
+
+// ContentTypes returns the content types quotes can be rendered in,
+// in order of preference
+func (r *Registry) ContentTypes() []string {
+	var contentTypes []string
+	for _, reg := range r.registrations {
+		contentTypes = append(contentTypes, reg.contentType)
+	}
+	return contentTypes
+}
+
+// PageContentTypes returns the content types pages of quotes can be
+// rendered in, in order of preference
+func (r *Registry) PageContentTypes() []string {
+	var contentTypes []string
+	for _, reg := range r.registrations {
+		if _, ok := reg.renderer.(PageRenderer); ok {
+			contentTypes = append(contentTypes, reg.contentType)
+		}
+	}
+	return contentTypes
+}
+
+// Formats returns the names of the Renderers
+func (r *Registry) Formats() []string {
+	var names []string
+	for _, reg := range r.registrations {
+		names = append(names, reg.name)
+	}
+	return names
+}
+
+// jsonRenderer writes quotes as JSON objects
+type jsonRenderer struct{}
+
+func (jsonRenderer) RenderQuote(w io.Writer, q *Quotation, _ RenderOptions) error {
+	return q.WriteJSON(w)
+}
+
+func (jsonRenderer) RenderPage(w io.Writer, p *Page, _ RenderOptions) error {
+	return p.WriteJSON(w)
+}
+
+// htmlRenderer writes quotes as HTML documents
+type htmlRenderer struct {
+	templates *Templates
+}
+
+func (r htmlRenderer) RenderQuote(w io.Writer, q *Quotation, opts RenderOptions) error {
+	return r.templates.ExecuteQuote(w, q, opts)
+}
+
+func (r htmlRenderer) RenderPage(w io.Writer, p *Page, opts RenderOptions) error {
+	return r.templates.ExecutePage(w, p, opts)
+}
+
+func (r htmlRenderer) RenderEmbed(w io.Writer, q *Quotation, opts RenderOptions) error {
+	return r.templates.ExecuteEmbed(w, q, opts)
+}
+
+// textRenderer writes quotes as plain text, e.g., for terminals
+type textRenderer struct{}
+
+func (textRenderer) RenderQuote(w io.Writer, q *Quotation, opts RenderOptions) error {
+	return q.WriteText(w, opts.Width)
+}
+
+func (textRenderer) RenderPage(w io.Writer, p *Page, opts RenderOptions) error {
+	return p.WriteText(w, opts.Width)
+}
+
+// markdownRenderer writes quotes as Markdown blockquotes
+type markdownRenderer struct{}
+
+func (markdownRenderer) RenderQuote(w io.Writer, q *Quotation, _ RenderOptions) error {
+	return q.WriteMarkdown(w)
+}
+
+func (markdownRenderer) RenderPage(w io.Writer, p *Page, _ RenderOptions) error {
+	return p.WriteMarkdown(w)
+}
@@ -0,0 +1,135 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quote
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// plainRenderer can only render single quotes
+type plainRenderer struct{}
+
+func (plainRenderer) RenderQuote(w io.Writer, q *Quotation, _ RenderOptions) error {
+	_, err := io.WriteString(w, q.Quote)
+	return err
+}
+
+func TestNewRegistry(t *testing.T) {
+	r := NewRegistry()
+	want := []string{
+		"application/json; charset=UTF-8",
+		"text/html; charset=UTF-8",
+		"text/plain; charset=UTF-8",
+		"text/markdown; charset=UTF-8",
+		"image/png",
+		"image/svg+xml; charset=UTF-8",
+	}
+	if got := r.ContentTypes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected content types %q, got %q", want, got)
+	}
+	// Images are only available for single quotes
+	if got := r.PageContentTypes(); !reflect.DeepEqual(got, want[:4]) {
+		t.Errorf("Expected page content types %q, got %q", want[:4], got)
+	}
+	if got, want := r.Formats(), []string{"json", "html", "text", "markdown", "png", "svg"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected formats %q, got %q", want, got)
+	}
+
+	renderer, ok := r.Lookup("text/html")
+	if !ok {
+		t.Fatal("Expected a renderer for text/html")
+	}
+	var buf bytes.Buffer
+	q := Quotation{Quote: "Rendered", Author: "Tester"}
+	if err := renderer.RenderQuote(&buf, &q, RenderOptions{}); err != nil {
+		t.Fatalf("RenderQuote failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "<q") {
+		t.Errorf("Expected HTML output, got %q", buf.String())
+	}
+}
+
+func TestRegistry_Register(t *testing.T) {
+	r := NewRegistry()
+	r.Register("gif", "image/gif", plainRenderer{})
+
+	if _, ok := r.Lookup("IMAGE/GIF"); !ok {
+		t.Error("Expected a renderer for image/gif")
+	}
+	if ct, ok := r.LookupFormat("gif"); !ok || ct != "image/gif" {
+		t.Errorf("Expected the gif format to be image/gif, got %q", ct)
+	}
+	if got := r.ContentTypes(); len(got) != 7 || got[6] != "image/gif" {
+		t.Errorf("Expected image/gif to be the last content type, got %q", got)
+	}
+	if got := r.PageContentTypes(); len(got) != 4 {
+		t.Errorf("Expected image/gif not to be a page content type, got %q", got)
+	}
+
+	// Replacing a renderer keeps its position
+	r.Register("json", "application/json", plainRenderer{})
+	if got := r.ContentTypes(); len(got) != 7 || got[0] != "application/json" {
+		t.Errorf("Expected application/json to stay first, got %q", got)
+	}
+	if renderer, _ := r.Lookup("application/json"); renderer != (plainRenderer{}) {
+		t.Errorf("Expected the application/json renderer to be replaced, got %T", renderer)
+	}
+
+	if _, ok := r.Lookup("image/webp"); ok {
+		t.Error("Expected no renderer for image/webp")
+	}
+	if _, ok := r.LookupFormat("webp"); ok {
+		t.Error("Expected no webp format")
+	}
+}
+
+func TestRegistry_RegisterInvalid(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a panic registering an invalid content type")
+		}
+	}()
+	NewRegistry().Register("invalid", "not a media type", plainRenderer{})
+}
+
+func TestTextRenderer(t *testing.T) {
+	q := Quotation{Quote: "Start before you are ready. Don't prepare, begin.", Author: "Mel Robbins"}
+	var buf bytes.Buffer
+	if err := (textRenderer{}).RenderQuote(&buf, &q, RenderOptions{Width: 20}); err != nil {
+		t.Fatalf("RenderQuote failed: %v", err)
+	}
+	want := "Start before you are\nready. Don't\nprepare, begin.\n— Mel Robbins\n"
+	if buf.String() != want {
+		t.Errorf("Expected %q, got %q", want, buf.String())
+	}
+}
+
+func TestMarkdownRenderer_Page(t *testing.T) {
+	p := Page{Quotes: []Quotation{{Quote: "One", Author: "A"}, {Quote: "Two"}}}
+	var buf bytes.Buffer
+	if err := (markdownRenderer{}).RenderPage(&buf, &p, RenderOptions{}); err != nil {
+		t.Fatalf("RenderPage failed: %v", err)
+	}
+	want := "> One\n>\n> — A\n\n> Two\n"
+	if buf.String() != want {
+		t.Errorf("Expected %q, got %q", want, buf.String())
+	}
+}
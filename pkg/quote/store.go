@@ -0,0 +1,80 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quote
+
+// Store is the interface implemented by the storage backends of Quotations
+type Store interface {
+	// GetQuote returns the quote identified by id, or an error wrapping
+	// ErrNotFound if it does not exist
+	GetQuote(id int) (*Quotation, error)
+	// RandomQuotation returns one of the stored quotes picked at random
+	RandomQuotation() (*Quotation, error)
+	// RandomQuotationMatching returns a quote picked at random among
+	// the ones selected by filter
+	RandomQuotationMatching(filter Filter) (*Quotation, error)
+	// AddQuote stores a new quote assigning it a unique ID,
+	// and returns the stored Quotation. Errors wrap ErrInvalid if the
+	// quote is not valid and ErrFull if there is no room for it.
+	AddQuote(quote Quotation) (*Quotation, error)
+	// AddQuotes stores all the given quotes, or none of them if any
+	// cannot be stored, and returns the stored Quotations
+	AddQuotes(quotes []Quotation) ([]Quotation, error)
+	// RestoreQuotes stores all the given quotes keeping their IDs, or none
+	// of them if any cannot be stored, e.g., because its ID is in use
+	RestoreQuotes(quotes []Quotation) ([]Quotation, error)
+	// CheckQuotes returns the error AddQuotes, or RestoreQuotes if keepIDs,
+	// would return for quotes, without storing them
+	CheckQuotes(quotes []Quotation, keepIDs bool) error
+	// UpdateQuote replaces the quote identified by id, keeping its ID
+	UpdateQuote(id int, quote Quotation) error
+	// PatchQuote changes the quote identified by id with patch, keeping its
+	// ID, and returns the stored Quotation. The quote cannot change in the
+	// meantime, so patch must not use the Store.
+	PatchQuote(id int, patch func(*Quotation) error) (*Quotation, error)
+	// DeleteQuote removes the quote identified by id
+	DeleteQuote(id int) error
+	// ListQuotes returns up to limit quotes starting from offset,
+	// or all the remaining ones if limit is not positive
+	ListQuotes(offset, limit int) ([]Quotation, error)
+	// ListQuotesAfter returns up to limit quotes with an ID greater than
+	// afterID, sorted by ID, or all of them if limit is not positive
+	ListQuotesAfter(afterID, limit int) ([]Quotation, error)
+	// Count returns the number of stored quotes
+	Count() int
+	// Search returns up to limit quotes matching query, the most relevant first
+	Search(query string, limit int) ([]SearchResult, error)
+	// Tags returns the tags of the stored quotes with their usage count
+	Tags() []TagCount
+	// PinnedQuotes returns the quotes pinned to keys, pinning the one at
+	// position choose(key, count) to the keys without one, so that adding
+	// or deleting other quotes does not change them
+	PinnedQuotes(keys []string, choose func(key string, count int) int) ([]Quotation, error)
+}
+
+// HealthChecker is implemented by the Stores which can fail to store
+// quotes, e.g., because of their backing file
+type HealthChecker interface {
+	// CheckHealth returns an error if the Store cannot store quotes
+	CheckHealth() error
+}
+
+var (
+	_ Store = (*QuoteBook)(nil)
+	_ Store = (*FileStore)(nil)
+
+	_ HealthChecker = (*FileStore)(nil)
+)
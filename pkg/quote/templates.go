@@ -0,0 +1,260 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quote
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+//go:embed templates/*.html
+var builtinTemplates embed.FS
+
+const (
+	// layoutTemplate defines the "layout" of all the pages, with the
+	// "title", "head", "style" and "content" templates
+	layoutTemplate = "layout.html"
+	// quoteTemplate defines the "title", "head" and "content" of the page of
+	// a quote, with its metadata for link previews
+	quoteTemplate = "quote.html"
+	// pageTemplate defines the "title" and "content" of a page of quotes
+	pageTemplate = "page.html"
+	// embedTemplate defines the "title", "head" and "content" of the page
+	// of a quote embedded in other pages
+	embedTemplate = "embed.html"
+)
+
+// templateFiles are the files templates are loaded from
+var templateFiles = []string{layoutTemplate, quoteTemplate, pageTemplate, embedTemplate}
+
+// Themes are the color themes of the HTML pages: "auto" follows the
+// settings of the client
+var Themes = []string{"auto", "light", "dark"}
+
+// DefaultTheme is the theme of the HTML pages when none is chosen
+const DefaultTheme = "auto"
+
+// TemplateData is what templates are executed with
+type TemplateData struct {
+	// Theme is one of Themes
+	Theme string
+	// BaseURL is the absolute URL quotes are served at, empty to link
+	// them with relative URLs
+	BaseURL string
+	// Quote is set when rendering a single quote
+	Quote *Quotation
+	// Page is set when rendering a Page of quotes
+	Page *Page
+}
+
+// Templates are the HTML templates quotes are rendered with. The built-in
+// ones can be replaced by files with the same name in a directory: Templates
+// are parsed once and parsed again only when reloaded.
+type Templates struct {
+	dir string
+
+	mu sync.RWMutex
+	// set maps the quote and page templates to their parsed version,
+	// along with the layout
+	set map[string]*template.Template
+	// stamp identifies the version of the files in dir which were parsed
+	stamp string
+}
+
+var defaultTemplates = sync.OnceValue(func() *Templates {
+	t, err := NewTemplates("")
+	if err != nil {
+		// The built-in templates are always valid
+		panic(err)
+	}
+	return t
+})
+
+// NewTemplates returns the Templates loaded from dir, using the built-in
+// ones for the files missing there. Only the built-in Templates are used
+// if dir is empty.
+func NewTemplates(dir string) (*Templates, error) {
+	t := &Templates{dir: dir}
+	if _, err := t.Reload(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Reload parses the templates again if the files in the template directory
+// changed since they were last parsed, and tells whether they did.
+// On errors the previous templates are kept.
+func (t *Templates) Reload() (bool, error) {
+	stamp, err := t.currentStamp()
+	if err != nil {
+		return false, err
+	}
+	t.mu.RLock()
+	unchanged := t.set != nil && stamp == t.stamp
+	t.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	set, err := t.parse()
+	if err != nil {
+		return false, err
+	}
+	t.mu.Lock()
+	t.set, t.stamp = set, stamp
+	t.mu.Unlock()
+	return true, nil
+}
+
+// Watch reloads the templates whenever the files in the template directory
+// change, checking them at every interval until ctx is done. Files are
+// polled so that changes are noticed on any file system, e.g., when
+// mounted in a container. If onReload is not nil, it is called after each
+// attempt to reload the templates with its error.
+func (t *Templates) Watch(ctx context.Context, interval time.Duration, onReload func(error)) {
+	if t.dir == "" {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reloaded, err := t.Reload()
+			if (reloaded || err != nil) && onReload != nil {
+				onReload(err)
+			}
+		}
+	}
+}
+
+// currentStamp returns a string identifying the current version of the
+// template files in the template directory
+func (t *Templates) currentStamp() (string, error) {
+	if t.dir == "" {
+		return "", nil
+	}
+	var stamp bytes.Buffer
+	for _, name := range templateFiles {
+		info, err := os.Stat(filepath.Join(t.dir, name))
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			continue
+		case err != nil:
+			return "", err
+		}
+		fmt.Fprintf(&stamp, "%s:%d:%d;", name, info.Size(), info.ModTime().UnixNano())
+	}
+	return stamp.String(), nil
+}
+
+// readTemplate returns the content of the template file name, from the
+// template directory if it is there
+func (t *Templates) readTemplate(name string) ([]byte, error) {
+	if t.dir != "" {
+		data, err := os.ReadFile(filepath.Join(t.dir, name))
+		if !errors.Is(err, fs.ErrNotExist) {
+			return data, err
+		}
+	}
+	return builtinTemplates.ReadFile("templates/" + name)
+}
+
+// parse parses the layout along with each of the other templates
+func (t *Templates) parse() (map[string]*template.Template, error) {
+	layout, err := t.readTemplate(layoutTemplate)
+	if err != nil {
+		return nil, err
+	}
+	base, err := template.New(layoutTemplate).Parse(string(layout))
+	if err != nil {
+		return nil, err
+	}
+
+	set := map[string]*template.Template{}
+	for _, name := range templateFiles {
+		if name == layoutTemplate {
+			continue
+		}
+		content, err := t.readTemplate(name)
+		if err != nil {
+			return nil, err
+		}
+		tmpl, err := template.Must(base.Clone()).New(name).Parse(string(content))
+		if err != nil {
+			return nil, err
+		}
+		if tmpl.Lookup("layout") == nil {
+			return nil, fmt.Errorf("template %q is not defined", "layout")
+		}
+		set[name] = tmpl
+	}
+	return set, nil
+}
+
+// execute writes the page of the template file name with data. Nothing is
+// written if the template fails.
+func (t *Templates) execute(w io.Writer, name string, data TemplateData) error {
+	if data.Theme == "" {
+		data.Theme = DefaultTheme
+	}
+	if !slices.Contains(Themes, data.Theme) {
+		return fmt.Errorf("unknown theme %q", data.Theme)
+	}
+
+	t.mu.RLock()
+	tmpl := t.set[name]
+	t.mu.RUnlock()
+
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, "layout", data); err != nil {
+		return err
+	}
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// ExecuteQuote writes the HTML page of q with the theme and base URL
+// of opts
+func (t *Templates) ExecuteQuote(w io.Writer, q *Quotation, opts RenderOptions) error {
+	return t.execute(w, quoteTemplate, TemplateData{Theme: opts.Theme, BaseURL: opts.BaseURL, Quote: q})
+}
+
+// ExecutePage writes the HTML page of p with the theme and base URL
+// of opts
+func (t *Templates) ExecutePage(w io.Writer, p *Page, opts RenderOptions) error {
+	return t.execute(w, pageTemplate, TemplateData{Theme: opts.Theme, BaseURL: opts.BaseURL, Page: p})
+}
+
+// ExecuteEmbed writes the compact HTML page of q to embed in other pages,
+// e.g., in an iframe, with the theme and base URL of opts
+func (t *Templates) ExecuteEmbed(w io.Writer, q *Quotation, opts RenderOptions) error {
+	return t.execute(w, embedTemplate, TemplateData{Theme: opts.Theme, BaseURL: opts.BaseURL, Quote: q})
+}
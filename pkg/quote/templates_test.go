@@ -0,0 +1,194 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quote
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeTemplate writes a template file in dir, with a modification time
+// different from the previous one
+func writeTemplate(t *testing.T, dir, name, content string, mtime time.Time) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTemplates_Builtin(t *testing.T) {
+	tmpl, err := NewTemplates("")
+	if err != nil {
+		t.Fatalf("NewTemplates failed: %v", err)
+	}
+	q := Quotation{Quote: "Be <bold>", Author: "Tester"}
+	for _, theme := range Themes {
+		var buf bytes.Buffer
+		if err := tmpl.ExecuteQuote(&buf, &q, RenderOptions{Theme: theme}); err != nil {
+			t.Fatalf("ExecuteQuote failed with theme %q: %v", theme, err)
+		}
+		for _, want := range []string{`<body class="theme-` + theme + `">`, "Be &lt;bold&gt;", "<title>Tester - Quotaday</title>", "@media"} {
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("Expected %q in page:\n%s", want, buf.String())
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteQuote(&buf, &q, RenderOptions{Theme: "sepia"}); err == nil {
+		t.Error("Expected an error with an unknown theme")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expected nothing written on errors, got %q", buf.String())
+	}
+}
+
+func TestTemplates_BaseURL(t *testing.T) {
+	q := Quotation{ID: 3, Quote: "Linked", Author: "Tester"}
+	opts := RenderOptions{BaseURL: "https://quote.example.com"}
+	tests := []struct {
+		name    string
+		execute func(io.Writer, *Quotation, RenderOptions) error
+		want    string
+	}{
+		{"quote", defaultTemplates().ExecuteQuote, `<link rel="alternate" type="application/json+oembed" href="https://quote.example.com/oembed?url=https%3a%2f%2fquote.example.com%2fquotes%2f3">`},
+		{"canonical", defaultTemplates().ExecuteQuote, `<link rel="canonical" href="https://quote.example.com/quotes/3">`},
+		{"open graph", defaultTemplates().ExecuteQuote, `<meta property="og:image" content="https://quote.example.com/quote/image?id=3&amp;format=png">`},
+		{"twitter card", defaultTemplates().ExecuteQuote, `<meta name="twitter:card" content="summary_large_image">`},
+		{"description", defaultTemplates().ExecuteQuote, `<meta name="description" content="Linked">`},
+		{"embed", defaultTemplates().ExecuteEmbed, `<a href="https://quote.example.com/quotes/3">Linked</a>`},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := tt.execute(&buf, &q, opts); err != nil {
+			t.Fatalf("%s: execute failed: %v", tt.name, err)
+		}
+		if !strings.Contains(buf.String(), tt.want) {
+			t.Errorf("%s: expected %q in page:\n%s", tt.name, tt.want, buf.String())
+		}
+	}
+}
+
+func TestTemplates_Dir(t *testing.T) {
+	dir := t.TempDir()
+	mtime := time.Now().Add(-time.Hour)
+	writeTemplate(t, dir, quoteTemplate, `{{define "content"}}custom {{.Quote.Quote}}{{end}}`, mtime)
+
+	tmpl, err := NewTemplates(dir)
+	if err != nil {
+		t.Fatalf("NewTemplates failed: %v", err)
+	}
+	q := Quotation{Quote: "Hello"}
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteQuote(&buf, &q, RenderOptions{}); err != nil {
+		t.Fatalf("ExecuteQuote failed: %v", err)
+	}
+	// The built-in layout is used along with the custom quote template
+	if got := buf.String(); !strings.Contains(got, "custom Hello") || !strings.Contains(got, "<html>") {
+		t.Errorf("Expected the custom template in the built-in layout, got:\n%s", got)
+	}
+
+	if reloaded, err := tmpl.Reload(); err != nil || reloaded {
+		t.Errorf("Expected no reload of unchanged templates, got %t, %v", reloaded, err)
+	}
+
+	writeTemplate(t, dir, quoteTemplate, `{{define "content"}}changed {{.Quote.Quote}}{{end}}`, mtime.Add(time.Minute))
+	if reloaded, err := tmpl.Reload(); err != nil || !reloaded {
+		t.Fatalf("Expected the changed templates reloaded, got %t, %v", reloaded, err)
+	}
+	buf.Reset()
+	if err := tmpl.ExecuteQuote(&buf, &q, RenderOptions{}); err != nil {
+		t.Fatalf("ExecuteQuote failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "changed Hello") {
+		t.Errorf("Expected the reloaded template, got:\n%s", buf.String())
+	}
+
+	writeTemplate(t, dir, quoteTemplate, `{{define "content"}}{{.Quote.Quote}`, mtime.Add(2*time.Minute))
+	if _, err := tmpl.Reload(); err == nil {
+		t.Fatal("Expected an error reloading an invalid template")
+	}
+	buf.Reset()
+	if err := tmpl.ExecuteQuote(&buf, &q, RenderOptions{}); err != nil || !strings.Contains(buf.String(), "changed Hello") {
+		t.Errorf("Expected the previous template kept, got %v:\n%s", err, buf.String())
+	}
+}
+
+func TestNewTemplates_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{"syntax error", pageTemplate, `{{range .Page.Quotes}}`},
+		{"no layout", layoutTemplate, `{{define "style"}}{{end}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeTemplate(t, dir, tt.file, tt.content, time.Now())
+			if _, err := NewTemplates(dir); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+func TestTemplates_Watch(t *testing.T) {
+	dir := t.TempDir()
+	mtime := time.Now().Add(-time.Hour)
+	writeTemplate(t, dir, quoteTemplate, `{{define "content"}}before{{end}}`, mtime)
+	tmpl, err := NewTemplates(dir)
+	if err != nil {
+		t.Fatalf("NewTemplates failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	reloads := make(chan error)
+	done := make(chan struct{})
+	go func() {
+		tmpl.Watch(ctx, time.Millisecond, func(err error) { reloads <- err })
+		close(done)
+	}()
+
+	writeTemplate(t, dir, quoteTemplate, `{{define "content"}}after{{end}}`, mtime.Add(time.Minute))
+	select {
+	case err := <-reloads:
+		if err != nil {
+			t.Fatalf("Reload failed: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the templates reloaded")
+	}
+	cancel()
+	<-done
+
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteQuote(&buf, &Quotation{}, RenderOptions{}); err != nil || !strings.Contains(buf.String(), "after") {
+		t.Errorf("Expected the reloaded template, got %v:\n%s", err, buf.String())
+	}
+}
@@ -0,0 +1,57 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quote
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// wrapText splits s in lines no longer than width characters, breaking
+// them between words. Line breaks in s are kept and words longer than
+// width get a line of their own. s is not wrapped if width is not positive.
+func wrapText(s string, width int) []string {
+	if width <= 0 {
+		return strings.Split(s, "\n")
+	}
+	return wrapWords(s, func(line string) bool {
+		return utf8.RuneCountInString(line) <= width
+	})
+}
+
+// wrapWords splits s in lines, adding as many words to each line as fits
+// allows. Line breaks in s are kept and words which do not fit alone get
+// a line of their own.
+func wrapWords(s string, fits func(line string) bool) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(s, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			switch {
+			case line == "":
+				line = word
+			case fits(line + " " + word):
+				line += " " + word
+			default:
+				lines = append(lines, line)
+				line = word
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
@@ -0,0 +1,43 @@
+/*
+Copyright © 2025 Francesco Giudici <dev@foggy.day>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package quote
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWrapText(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  []string
+	}{
+		{"No wrapping at all", 0, []string{"No wrapping at all"}},
+		{"Kept\nline breaks", 0, []string{"Kept", "line breaks"}},
+		{"Wrapped between words", 10, []string{"Wrapped", "between", "words"}},
+		{"Fits exactly", 12, []string{"Fits exactly"}},
+		{"A veryveryverylong word", 5, []string{"A", "veryveryverylong", "word"}},
+		{"Più è già così", 6, []string{"Più è", "già", "così"}},
+		{"First  paragraph\n\nsecond one", 20, []string{"First paragraph", "", "second one"}},
+	}
+	for _, tt := range tests {
+		if got := wrapText(tt.s, tt.width); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("wrapText(%q, %d) = %q, expected %q", tt.s, tt.width, got, tt.want)
+		}
+	}
+}